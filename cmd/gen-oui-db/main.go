@@ -1,40 +1,31 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
-	"encoding/binary"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
-	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	mactracker "github.com/runZeroInc/mac-tracker"
+	"github.com/runZeroInc/mac-tracker/internal/ieee"
 )
 
 type registrationEntry struct {
-	Date    string `json:"d"`
-	Type    string `json:"t"`
-	Source  string `json:"s"`
-	Address string `json:"a"`
-	Country string `json:"c"`
-	Org     string `json:"o"`
-}
-
-type ouiEntry struct {
-	oui     [6]byte
-	mask    int
-	vendor  string
-	added   string
-	country string
-	address string
+	Date     string `json:"d"`
+	Type     string `json:"t"`
+	Source   string `json:"s"`
+	Address  string `json:"a"`
+	Country  string `json:"c"`
+	Org      string `json:"o"`
+	Registry string `json:"r,omitempty"`
 }
 
-var ouiMagic = [4]byte{'O', 'U', 'I', 0x01}
-
 func main() {
 	dir := findBaseDir()
 
@@ -52,7 +43,7 @@ func main() {
 
 	log.Printf("Processing %d MAC prefixes", len(macData))
 
-	entries := make([]ouiEntry, 0, len(macData))
+	db := &mactracker.OuiDB{Blocks: make(map[string]*mactracker.OuiBlock, len(macData))}
 	for prefix, regs := range macData {
 		parts := strings.SplitN(prefix, "/", 2)
 		maskStr := "24"
@@ -79,6 +70,7 @@ func main() {
 		lastOrg := ""
 		lastCountry := ""
 		lastAddress := ""
+		removed := ""
 		for _, entry := range regs {
 			if firstAdded == "" && entry.Type == "add" {
 				firstAdded = strings.TrimSpace(entry.Date)
@@ -86,26 +78,44 @@ func main() {
 			lastOrg = strings.TrimSpace(entry.Org)
 			lastAddress = strings.TrimSpace(entry.Address)
 			lastCountry = strings.TrimSpace(entry.Country)
+			// A removal stays in effect until the prefix is re-added
+			removed = ""
+			if entry.Type == "remove" {
+				removed = strings.TrimSpace(entry.Date)
+			}
 		}
 
-		entries = append(entries, ouiEntry{
-			oui:     oui,
-			mask:    mask,
-			vendor:  sanitize(lastOrg),
-			added:   firstAdded,
-			country: sanitize(strings.ToUpper(lastCountry)),
-			address: sanitize(lastAddress),
-		})
+		key := hex.EncodeToString(oui[:]) + "/" + strconv.Itoa(mask)
+		vendor := sanitize(lastOrg)
+		address := sanitize(lastAddress)
+		addrParts := ieee.ParseAddress(address)
+		country := addrParts.Country
+		if country == "" {
+			country = sanitize(strings.ToUpper(lastCountry))
+		}
+		db.Blocks[key] = &mactracker.OuiBlock{
+			Oui:         oui[:],
+			Mask:        mask,
+			Vendor:      vendor,
+			VendorShort: mactracker.NormalizeVendor(vendor),
+			OrgID:       mactracker.VendorOrgID(vendor),
+			Added:       firstAdded,
+			Country:     country,
+			Address:     address,
+			Street:      addrParts.Street,
+			City:        addrParts.City,
+			Region:      addrParts.Region,
+			PostalCode:  addrParts.PostalCode,
+			Removed:     removed,
+			Registry:    registry(prefix, regs),
+		}
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		ki := hex.EncodeToString(entries[i].oui[:]) + "/" + strconv.Itoa(entries[i].mask)
-		kj := hex.EncodeToString(entries[j].oui[:]) + "/" + strconv.Itoa(entries[j].mask)
-		return ki < kj
-	})
+	db.Info.Built = time.Now().UTC()
+	db.Info.Sources = sourceChecksums(dir)
 
-	log.Printf("Encoding %d entries", len(entries))
-	data, err := encode(entries)
+	log.Printf("Encoding %d entries", len(db.Blocks))
+	data, err := mactracker.EncodeOUIDB(db)
 	if err != nil {
 		log.Fatalf("encode: %v", err)
 	}
@@ -115,50 +125,38 @@ func main() {
 		log.Fatalf("write: %v", err)
 	}
 
-	log.Printf("Wrote %s (%d bytes, %d entries)", outPath, len(data), len(entries))
+	log.Printf("Wrote %s (%d bytes, %d entries)", outPath, len(data), len(db.Blocks))
 }
 
-func encode(entries []ouiEntry) ([]byte, error) {
-	var raw bytes.Buffer
+// sourceChecksums returns the SHA-256 of macs.json and each IEEE CSV in the data directory.
+func sourceChecksums(dir string) []mactracker.OuiSource {
+	paths, _ := filepath.Glob(filepath.Join(dir, "data", "ieee", "*.csv"))
+	sort.Strings(paths)
+	paths = append([]string{filepath.Join(dir, "data", "macs.json")}, paths...)
 
-	raw.Write(ouiMagic[:])
-	if err := binary.Write(&raw, binary.LittleEndian, uint32(len(entries))); err != nil {
-		return nil, err
-	}
-
-	for _, e := range entries {
-		raw.Write(e.oui[:])
-		raw.WriteByte(byte(e.mask))
-		for _, s := range []string{e.vendor, e.added, e.country, e.address} {
-			if err := writeStr(&raw, s); err != nil {
-				return nil, err
-			}
+	sources := make([]mactracker.OuiSource, 0, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			log.Fatalf("checksum %s: %v", p, err)
 		}
+		sources = append(sources, mactracker.OuiSource{Name: filepath.Base(p), SHA256: sha256.Sum256(data)})
 	}
-
-	var compressed bytes.Buffer
-	gz, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
-	if err != nil {
-		return nil, err
-	}
-	if _, err := gz.Write(raw.Bytes()); err != nil {
-		return nil, err
-	}
-	if err := gz.Close(); err != nil {
-		return nil, err
-	}
-	return compressed.Bytes(), nil
+	return sources
 }
 
-func writeStr(w *bytes.Buffer, s string) error {
-	if len(s) > 65535 {
-		return fmt.Errorf("string too long: %d", len(s))
-	}
-	if err := binary.Write(w, binary.LittleEndian, uint16(len(s))); err != nil {
-		return err
+// registry returns the IEEE registry of a prefix, from its latest entry with
+// a registry or an IEEE source file, or else from the block size.
+func registry(prefix string, regs []registrationEntry) string {
+	for i := len(regs) - 1; i >= 0; i-- {
+		if regs[i].Registry != "" {
+			return regs[i].Registry
+		}
+		if r, ok := ieee.SourceRegistries[regs[i].Source]; ok {
+			return r
+		}
 	}
-	w.WriteString(s)
-	return nil
+	return ieee.PrefixRegistry(prefix)
 }
 
 func sanitize(s string) string {
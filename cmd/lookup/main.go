@@ -1,19 +1,145 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
 	"os"
+	"time"
 
 	mactracker "github.com/runZeroInc/mac-tracker"
 )
 
 func main() {
-	for _, v := range os.Args[1:] {
-		block := mactracker.Lookup(v)
+	historyPath := flag.String("history", "", "path to a macs.json registration history; prints each prefix's history")
+	at := flag.String("at", "", "resolve addresses as of this date (YYYY-MM-DD); requires -history")
+	bitOrder := flag.String("bit-order", "canonical", "bit order of the input: canonical, reversed (Token Ring/FDDI) or auto")
+	activeOnly := flag.Bool("active-only", false, "skip prefixes that were removed from their IEEE registry")
+	flag.Parse()
+
+	if flag.Arg(0) == "search" {
+		searchVendors(flag.Args()[1:])
+		return
+	}
+	if *historyPath != "" {
+		lookupHistory(*historyPath, *at, flag.Args())
+		return
+	}
+	if *at != "" {
+		log.Fatal("-at requires -history")
+	}
+
+	var order mactracker.BitOrder
+	switch *bitOrder {
+	case "canonical":
+		order = mactracker.BitOrderCanonical
+	case "reversed":
+		order = mactracker.BitOrderReversed
+	case "auto":
+		order = mactracker.BitOrderAuto
+	default:
+		log.Fatalf("bad -bit-order %q", *bitOrder)
+	}
+
+	mactracker.DefaultResolver().SetActiveOnly(*activeOnly)
+	for _, v := range flag.Args() {
+		block, addr, matched := mactracker.DefaultResolver().LookupBitOrder(v, order)
 		if block == nil {
 			fmt.Printf("%s: No match found\n", v)
 			continue
 		}
+		if matched == mactracker.BitOrderReversed {
+			v = fmt.Sprintf("%s (bit-reversed %s)", v, addr)
+		}
+		if !block.Active() {
+			v = fmt.Sprintf("%s (removed %s)", v, block.Removed)
+		}
+		if block.Registry != "" {
+			fmt.Printf("%s: [%s %s] %s - %s\n", v, block.Added, block.Registry, block.Vendor, block.Address)
+			continue
+		}
 		fmt.Printf("%s: [%s] %s - %s\n", v, block.Added, block.Vendor, block.Address)
 	}
 }
+
+func lookupHistory(path, at string, macs []string) {
+	history, err := mactracker.LoadHistoryFile(path)
+	if err != nil {
+		log.Fatalf("load history: %v", err)
+	}
+
+	if at == "" {
+		for _, v := range macs {
+			regs := history.Lookup(v)
+			if len(regs) == 0 {
+				fmt.Printf("%s: No match found\n", v)
+				continue
+			}
+			fmt.Printf("%s: %s\n", v, regs[0].Prefix)
+			for _, reg := range regs {
+				fmt.Printf("  %s %-6s %s - %s\n", reg.Date, reg.Type, reg.Org, reg.Address)
+			}
+		}
+		return
+	}
+
+	when, err := time.Parse("2006-01-02", at)
+	if err != nil {
+		log.Fatalf("bad date %q: %v", at, err)
+	}
+	for _, v := range macs {
+		reg := history.LookupAt(v, when)
+		if reg == nil {
+			fmt.Printf("%s: No match found on %s\n", v, at)
+			continue
+		}
+		fmt.Printf("%s: [%s %s] %s - %s\n", v, reg.Prefix, reg.Date, reg.Org, reg.Address)
+	}
+}
+
+// searchVendors prints every prefix registered to the vendors matching each
+// query, searching the tables in lookup order. A prefix listed in an earlier
+// table hides the same prefix in later ones, as it does for lookups.
+func searchVendors(args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	exact := fs.Bool("exact", false, "match the vendor name exactly")
+	normalized := fs.Bool("normalized", false, "match the vendor name ignoring case, punctuation and company suffixes")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s search [-exact | -normalized] <vendor>...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if *exact && *normalized {
+		log.Fatal("-exact and -normalized are mutually exclusive")
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	match := mactracker.VendorSubstring
+	switch {
+	case *exact:
+		match = mactracker.VendorExact
+	case *normalized:
+		match = mactracker.VendorNormalized
+	}
+
+	tables := mactracker.DefaultResolver().Tables()
+	for _, q := range fs.Args() {
+		seen := make(map[string]struct{})
+		for _, table := range tables {
+			for _, block := range table.SearchVendor(q, match) {
+				key := block.Key()
+				if _, dup := seen[key]; dup {
+					continue
+				}
+				seen[key] = struct{}{}
+				fmt.Printf("%s: %s %s\n", q, key, block.Vendor)
+			}
+		}
+		if len(seen) == 0 {
+			fmt.Printf("%s: No match found\n", q)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// PrefixChange is a single prefix that changed in an update run.
+type PrefixChange struct {
+	Prefix      string `json:"prefix"`
+	Registry    string `json:"registry,omitempty"`
+	OldRegistry string `json:"old_registry,omitempty"`
+	Org         string `json:"org"`
+	OldOrg      string `json:"old_org,omitempty"`
+	Address     string `json:"address,omitempty"`
+	OldAddress  string `json:"old_address,omitempty"`
+	Country     string `json:"country,omitempty"`
+	Source      string `json:"source,omitempty"` // Registry file a removed prefix was last listed in
+}
+
+// Changelog lists what an update run changed in the registration history.
+// A prefix is renamed when its organization changed, whether or not the
+// address changed too, and moved when only its registry changed. Removed
+// prefixes are no longer listed in their registry file; re-added ones are
+// listed again after a removal.
+type Changelog struct {
+	Date      string         `json:"date"`
+	Added     []PrefixChange `json:"added"`
+	Renamed   []PrefixChange `json:"renamed"`
+	Addresses []PrefixChange `json:"address_changes"`
+	Moved     []PrefixChange `json:"registry_changes"`
+	Removed   []PrefixChange `json:"removed"`
+	Readded   []PrefixChange `json:"readded"`
+}
+
+// changelogRows caps each Markdown table so the report fits in a pull
+// request body; changelog.json always has every change.
+const changelogRows = 100
+
+// Empty reports whether the run changed nothing.
+func (c *Changelog) Empty() bool {
+	return len(c.Added)+len(c.Renamed)+len(c.Addresses)+len(c.Moved)+len(c.Removed)+len(c.Readded) == 0
+}
+
+// Summary describes the number of changes of each kind.
+func (c *Changelog) Summary() string {
+	if c.Empty() {
+		return "No changes"
+	}
+	return fmt.Sprintf("%d new, %d renamed, %d address changes, %d registry changes, %d removed, %d re-added",
+		len(c.Added), len(c.Renamed), len(c.Addresses), len(c.Moved), len(c.Removed), len(c.Readded))
+}
+
+// sort orders each kind of change by prefix, largest blocks first, as in mac-ages.csv.
+func (c *Changelog) sort() {
+	for _, changes := range [][]PrefixChange{c.Added, c.Renamed, c.Addresses, c.Moved, c.Removed, c.Readded} {
+		sort.Slice(changes, func(i, j int) bool {
+			return sortablePrefix(changes[i].Prefix) < sortablePrefix(changes[j].Prefix)
+		})
+	}
+}
+
+// WriteJSON writes the changelog as indented JSON.
+func (c *Changelog) WriteJSON(w io.Writer) error {
+	c.sort()
+	// Encode empty lists as [] rather than null
+	for _, changes := range []*[]PrefixChange{&c.Added, &c.Renamed, &c.Addresses, &c.Moved, &c.Removed, &c.Readded} {
+		if *changes == nil {
+			*changes = []PrefixChange{}
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// WriteMarkdown writes the changelog as a Markdown report with a table for
+// each kind of change.
+func (c *Changelog) WriteMarkdown(w io.Writer) error {
+	c.sort()
+	var b strings.Builder
+	fmt.Fprintf(&b, "## IEEE registry changes for %s\n\n%s.\n", c.Date, c.Summary())
+
+	table := func(title string, header []string, changes []PrefixChange, row func(PrefixChange) []string) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n### %s (%d)\n\n", title, len(changes))
+		b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+		b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+		for i, ch := range changes {
+			if i == changelogRows {
+				fmt.Fprintf(&b, "\n...and %d more, see changelog.json.\n", len(changes)-changelogRows)
+				break
+			}
+			cells := row(ch)
+			for j := range cells {
+				cells[j] = markdownCell(cells[j])
+			}
+			b.WriteString("| `" + ch.Prefix + "` | " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+
+	table("New prefixes", []string{"Prefix", "Registry", "Organization", "Country"}, c.Added, func(ch PrefixChange) []string {
+		return []string{ch.Registry, ch.Org, ch.Country}
+	})
+	table("Renamed", []string{"Prefix", "Registry", "Old organization", "New organization"}, c.Renamed, func(ch PrefixChange) []string {
+		return []string{ch.Registry, ch.OldOrg, ch.Org}
+	})
+	table("Address changes", []string{"Prefix", "Organization", "Old address", "New address"}, c.Addresses, func(ch PrefixChange) []string {
+		return []string{ch.Org, ch.OldAddress, ch.Address}
+	})
+	table("Registry changes", []string{"Prefix", "Organization", "Old registry", "New registry"}, c.Moved, func(ch PrefixChange) []string {
+		return []string{ch.Org, ch.OldRegistry, ch.Registry}
+	})
+	table("Removed", []string{"Prefix", "Registry", "Organization", "Last listed in"}, c.Removed, func(ch PrefixChange) []string {
+		return []string{ch.Registry, ch.Org, ch.Source}
+	})
+	table("Re-added", []string{"Prefix", "Registry", "Old organization", "Organization"}, c.Readded, func(ch PrefixChange) []string {
+		return []string{ch.Registry, ch.OldOrg, ch.Org}
+	})
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell flattens a value onto one line and escapes table separators.
+func markdownCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// writeChangelog writes the changelog next to macs.json as changelog.json
+// and changelog.md.
+func writeChangelog(info *MACUpdate) error {
+	for _, out := range []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"changelog.json", info.changelog().WriteJSON},
+		{"changelog.md", info.changelog().WriteMarkdown},
+	} {
+		f, err := os.Create(info.dataPath(out.name))
+		if err != nil {
+			return err
+		}
+		if err := out.write(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findRemoved records a removal for the prefixes last listed in an IEEE
+// registry file but missing from all of them. It only runs when every
+// registry file was read, since a prefix that moved to a registry left out
+// with -sources would otherwise be recorded as removed.
+func findRemoved(info *MACUpdate, seen map[string]bool) {
+	if len(info.registryFiles()) < len(ieeeFiles) {
+		log.Printf("Skipping removal detection, only some registry files were read")
+		return
+	}
+	read := make(map[string]bool)
+	for _, f := range info.registryFiles() {
+		read["ieee-"+f.name] = true
+	}
+	changes := info.changelog()
+	for prefix, entries := range info.data {
+		if len(entries) == 0 || seen[prefix] {
+			continue
+		}
+		last := entries[len(entries)-1]
+		if !read[last.Source] || last.Type == "remove" {
+			continue
+		}
+		registry := registryForEntries(prefix, entries)
+		info.data[prefix] = append(entries, RegistrationEntry{
+			Date:     info.today,
+			Type:     "remove",
+			Source:   last.Source,
+			Address:  last.Address,
+			Country:  last.Country,
+			Org:      last.Org,
+			Registry: registry,
+		})
+		changes.Removed = append(changes.Removed, PrefixChange{
+			Prefix:   prefix,
+			Registry: registry,
+			Org:      last.Org,
+			Address:  last.Address,
+			Country:  last.Country,
+			Source:   last.Source,
+		})
+	}
+}
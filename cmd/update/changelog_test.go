@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestChangelog(t *testing.T) {
+	info := &MACUpdate{
+		ages:  make(MACAges),
+		today: "2026-01-27",
+		files: ieeeFiles,
+		data: MACData{
+			"001bc5000000/24": {{Date: "2008-01-01", Type: "add", Source: "ieee-oui.csv", Org: "Old Name", Address: "1 Main St Springfield IL US 62701", Registry: "MA-L"}},
+			"0050c2123000/36": {{Date: "2008-01-01", Type: "add", Source: "ieee-iab.csv", Org: "Lab | Gear", Address: "Old Road Uxbridge  GB UB8 1JG"}},
+			"8c1f64ffc000/36": {{Date: "2020-01-01", Type: "add", Source: "ieee-oui36.csv", Org: "Mover", Registry: "MA-M"}},
+			"70b3d5c3c000/36": {{Date: "2015-01-01", Type: "add", Source: "ieee-oui36.csv", Org: "PEEK TRAFFIC", Registry: "MA-S"}},
+			"000e02000000/24": {{Date: "2003-09-08", Type: "add", Source: "wireshark.org", Org: "Advantech AMT Inc."}},
+			"8c1f64aaa000/36": {
+				{Date: "2021-01-01", Type: "add", Source: "ieee-oui36.csv", Org: "Lapsed", Registry: "MA-S"},
+				{Date: "2023-01-01", Type: "remove", Source: "ieee-oui36.csv", Org: "Lapsed", Registry: "MA-S"},
+			},
+		},
+	}
+	seen := map[string]bool{}
+	update := func(addr, org, address, source, registry string) {
+		updateRegistration(info, addr, info.today, org, address, source, registry)
+		seen[addr] = true
+	}
+	update("001bc5000000/24", "New Name", "1 Main St Springfield IL US 62701", "ieee-oui.csv", "MA-L")
+	update("0050c2123000/36", "Lab | Gear", "New Road Uxbridge  GB UB8 1JG", "ieee-iab.csv", "IAB")
+	update("8c1f64ffc000/36", "Mover", "", "ieee-oui36.csv", "MA-S")
+	update("3c6a2c000000/24", "Newcomer", "Berlin  DE 10115", "ieee-oui.csv", "MA-L")
+	update("8c1f64aaa000/36", "Renewed", "", "ieee-oui36.csv", "MA-S")
+	findRemoved(info, seen)
+
+	c := info.changelog()
+	check := func(kind string, changes []PrefixChange, want string) {
+		t.Helper()
+		if len(changes) != 1 || changes[0].Prefix != want {
+			t.Errorf("%s = %+v, want %s", kind, changes, want)
+		}
+	}
+	check("added", c.Added, "3c6a2c000000/24")
+	check("renamed", c.Renamed, "001bc5000000/24")
+	check("address changes", c.Addresses, "0050c2123000/36")
+	check("registry changes", c.Moved, "8c1f64ffc000/36")
+	// The Wireshark-era prefix was never listed by the IEEE, so it isn't removed
+	check("removed", c.Removed, "70b3d5c3c000/36")
+	check("re-added", c.Readded, "8c1f64aaa000/36")
+
+	// Removals and re-adds are recorded in the history once
+	findRemoved(info, seen)
+	if regs := info.data["70b3d5c3c000/36"]; len(regs) != 2 || regs[1].Type != "remove" || regs[1].Date != "2026-01-27" || regs[1].Org != "PEEK TRAFFIC" {
+		t.Errorf("history after removal = %+v", regs)
+	}
+	if regs := info.data["8c1f64aaa000/36"]; len(regs) != 3 || regs[2].Type != "re-add" || regs[2].Org != "Renewed" {
+		t.Errorf("history after re-add = %+v", regs)
+	}
+	if len(c.Removed) != 1 {
+		t.Errorf("second pass found %d removals, want 1", len(c.Removed))
+	}
+
+	// A run over some of the registries can't tell a removal from a move
+	// to a registry it didn't read
+	info.files = ieeeFiles[:1]
+	delete(seen, "001bc5000000/24")
+	findRemoved(info, seen)
+	if regs := info.data["001bc5000000/24"]; regs[len(regs)-1].Type == "remove" || len(c.Removed) != 1 {
+		t.Errorf("partial run recorded a removal: %+v", regs)
+	}
+
+	if got := c.Renamed[0]; got.OldOrg != "Old Name" || got.Org != "New Name" || got.OldAddress != "" {
+		t.Errorf("rename = %+v", got)
+	}
+	if got := c.Moved[0]; got.OldRegistry != "MA-M" || got.Registry != "MA-S" {
+		t.Errorf("registry change = %+v", got)
+	}
+	if got := c.Added[0]; got.Country != "DE" || got.Registry != "MA-L" {
+		t.Errorf("addition = %+v", got)
+	}
+	if got := c.Summary(); got != "1 new, 1 renamed, 1 address changes, 1 registry changes, 1 removed, 1 re-added" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	var md bytes.Buffer
+	if err := c.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"## IEEE registry changes for 2026-01-27\n",
+		"### New prefixes (1)\n",
+		"| `3c6a2c000000/24` | MA-L | Newcomer | DE |\n",
+		"| `001bc5000000/24` | MA-L | Old Name | New Name |\n",
+		"| `0050c2123000/36` | Lab \\| Gear | Old Road Uxbridge GB UB8 1JG | New Road Uxbridge GB UB8 1JG |\n",
+		"| `70b3d5c3c000/36` | MA-S | PEEK TRAFFIC | ieee-oui36.csv |\n",
+		"| `8c1f64aaa000/36` | MA-S | Lapsed | Renewed |\n",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown is missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var js bytes.Buffer
+	if err := c.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Changelog
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Date != "2026-01-27" || len(decoded.Removed) != 1 || decoded.Renamed[0].OldOrg != "Old Name" {
+		t.Errorf("JSON round trip = %+v", decoded)
+	}
+}
+
+func TestChangelogMarkdownLimits(t *testing.T) {
+	var md bytes.Buffer
+	if err := (&Changelog{Date: "2026-01-27"}).WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	if md.String() != "## IEEE registry changes for 2026-01-27\n\nNo changes.\n" {
+		t.Errorf("empty changelog = %q", md.String())
+	}
+
+	c := &Changelog{Date: "2026-01-27"}
+	for i := range changelogRows + 5 {
+		c.Added = append(c.Added, PrefixChange{Prefix: fmt.Sprintf("%012x/24", i<<24), Org: "Vendor"})
+	}
+	md.Reset()
+	if err := c.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	if rows := strings.Count(md.String(), "| Vendor |"); rows != changelogRows {
+		t.Errorf("Markdown has %d rows, want %d", rows, changelogRows)
+	}
+	if !strings.Contains(md.String(), "...and 5 more, see changelog.json.") {
+		t.Errorf("Markdown doesn't mention the rows left out:\n%s", md.String())
+	}
+}
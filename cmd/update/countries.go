@@ -0,0 +1,539 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/runZeroInc/mac-tracker/internal/ieee"
+)
+
+// countryNames maps the upper-case ISO 3166-1 short, official and common
+// names of each country to its alpha-2 code.
+var countryNames = map[string]string{
+	"AFGHANISTAN":                      "AF",
+	"ALBANIA":                          "AL",
+	"ALGERIA":                          "DZ",
+	"AMERICAN SAMOA":                   "AS",
+	"ANDORRA":                          "AD",
+	"ANGOLA":                           "AO",
+	"ANGUILLA":                         "AI",
+	"ANTARCTICA":                       "AQ",
+	"ANTIGUA AND BARBUDA":              "AG",
+	"ARAB REPUBLIC OF EGYPT":           "EG",
+	"ARGENTINA":                        "AR",
+	"ARGENTINE REPUBLIC":               "AR",
+	"ARMENIA":                          "AM",
+	"ARUBA":                            "AW",
+	"AUSTRALIA":                        "AU",
+	"AUSTRIA":                          "AT",
+	"AZERBAIJAN":                       "AZ",
+	"BAHAMAS":                          "BS",
+	"BAHRAIN":                          "BH",
+	"BANGLADESH":                       "BD",
+	"BARBADOS":                         "BB",
+	"BELARUS":                          "BY",
+	"BELGIUM":                          "BE",
+	"BELIZE":                           "BZ",
+	"BENIN":                            "BJ",
+	"BERMUDA":                          "BM",
+	"BHUTAN":                           "BT",
+	"BOLIVARIAN REPUBLIC OF VENEZUELA": "VE",
+	"BOLIVIA":                          "BO",
+	"BOLIVIA, PLURINATIONAL STATE OF":  "BO",
+	"BONAIRE, SINT EUSTATIUS AND SABA": "BQ",
+	"BOSNIA AND HERZEGOVINA":           "BA",
+	"BOTSWANA":                         "BW",
+	"BOUVET ISLAND":                    "BV",
+	"BRAZIL":                           "BR",
+	"BRITISH INDIAN OCEAN TERRITORY":   "IO",
+	"BRITISH VIRGIN ISLANDS":           "VG",
+	"BRUNEI DARUSSALAM":                "BN",
+	"BULGARIA":                         "BG",
+	"BURKINA FASO":                     "BF",
+	"BURUNDI":                          "BI",
+	"CABO VERDE":                       "CV",
+	"CAMBODIA":                         "KH",
+	"CAMEROON":                         "CM",
+	"CANADA":                           "CA",
+	"CAYMAN ISLANDS":                   "KY",
+	"CENTRAL AFRICAN REPUBLIC":         "CF",
+	"CHAD":                             "TD",
+	"CHILE":                            "CL",
+	"CHINA":                            "CN",
+	"CHRISTMAS ISLAND":                 "CX",
+	"COCOS (KEELING) ISLANDS":          "CC",
+	"COLOMBIA":                         "CO",
+	"COMMONWEALTH OF DOMINICA":         "DM",
+	"COMMONWEALTH OF THE BAHAMAS":      "BS",
+	"COMMONWEALTH OF THE NORTHERN MARIANA ISLANDS": "MP",
+	"COMOROS":                               "KM",
+	"CONGO":                                 "CG",
+	"CONGO, THE DEMOCRATIC REPUBLIC OF THE": "CD",
+	"COOK ISLANDS":                          "CK",
+	"COSTA RICA":                            "CR",
+	"CROATIA":                               "HR",
+	"CUBA":                                  "CU",
+	"CURAÇAO":                               "CW",
+	"CYPRUS":                                "CY",
+	"CZECH REPUBLIC":                        "CZ",
+	"CZECHIA":                               "CZ",
+	"CÔTE D'IVOIRE":                         "CI",
+	"DEMOCRATIC PEOPLE'S REPUBLIC OF KOREA": "KP",
+	"DEMOCRATIC REPUBLIC OF SAO TOME AND PRINCIPE": "ST",
+	"DEMOCRATIC REPUBLIC OF TIMOR-LESTE":           "TL",
+	"DEMOCRATIC SOCIALIST REPUBLIC OF SRI LANKA":   "LK",
+	"DENMARK":                     "DK",
+	"DJIBOUTI":                    "DJ",
+	"DOMINICA":                    "DM",
+	"DOMINICAN REPUBLIC":          "DO",
+	"EASTERN REPUBLIC OF URUGUAY": "UY",
+	"ECUADOR":                     "EC",
+	"EGYPT":                       "EG",
+	"EL SALVADOR":                 "SV",
+	"EQUATORIAL GUINEA":           "GQ",
+	"ERITREA":                     "ER",
+	"ESTONIA":                     "EE",
+	"ESWATINI":                    "SZ",
+	"ETHIOPIA":                    "ET",
+	"FALKLAND ISLANDS (MALVINAS)": "FK",
+	"FAROE ISLANDS":               "FO",
+	"FEDERAL DEMOCRATIC REPUBLIC OF ETHIOPIA": "ET",
+	"FEDERAL DEMOCRATIC REPUBLIC OF NEPAL":    "NP",
+	"FEDERAL REPUBLIC OF GERMANY":             "DE",
+	"FEDERAL REPUBLIC OF NIGERIA":             "NG",
+	"FEDERAL REPUBLIC OF SOMALIA":             "SO",
+	"FEDERATED STATES OF MICRONESIA":          "FM",
+	"FEDERATIVE REPUBLIC OF BRAZIL":           "BR",
+	"FIJI":                                    "FJ",
+	"FINLAND":                                 "FI",
+	"FRANCE":                                  "FR",
+	"FRENCH GUIANA":                           "GF",
+	"FRENCH POLYNESIA":                        "PF",
+	"FRENCH REPUBLIC":                         "FR",
+	"FRENCH SOUTHERN TERRITORIES":             "TF",
+	"GABON":                                   "GA",
+	"GABONESE REPUBLIC":                       "GA",
+	"GAMBIA":                                  "GM",
+	"GEORGIA":                                 "GE",
+	"GERMANY":                                 "DE",
+	"GHANA":                                   "GH",
+	"GIBRALTAR":                               "GI",
+	"GRAND DUCHY OF LUXEMBOURG":               "LU",
+	"GREECE":                                  "GR",
+	"GREENLAND":                               "GL",
+	"GRENADA":                                 "GD",
+	"GUADELOUPE":                              "GP",
+	"GUAM":                                    "GU",
+	"GUATEMALA":                               "GT",
+	"GUERNSEY":                                "GG",
+	"GUINEA":                                  "GN",
+	"GUINEA-BISSAU":                           "GW",
+	"GUYANA":                                  "GY",
+	"HAITI":                                   "HT",
+	"HASHEMITE KINGDOM OF JORDAN":             "JO",
+	"HEARD ISLAND AND MCDONALD ISLANDS":       "HM",
+	"HELLENIC REPUBLIC":                       "GR",
+	"HOLY SEE (VATICAN CITY STATE)":           "VA",
+	"HONDURAS":                                "HN",
+	"HONG KONG":                               "HK",
+	"HONG KONG SPECIAL ADMINISTRATIVE REGION OF CHINA": "HK",
+	"HUNGARY":                                "HU",
+	"ICELAND":                                "IS",
+	"INDEPENDENT STATE OF PAPUA NEW GUINEA":  "PG",
+	"INDEPENDENT STATE OF SAMOA":             "WS",
+	"INDIA":                                  "IN",
+	"INDONESIA":                              "ID",
+	"IRAN":                                   "IR",
+	"IRAN, ISLAMIC REPUBLIC OF":              "IR",
+	"IRAQ":                                   "IQ",
+	"IRELAND":                                "IE",
+	"ISLAMIC REPUBLIC OF AFGHANISTAN":        "AF",
+	"ISLAMIC REPUBLIC OF IRAN":               "IR",
+	"ISLAMIC REPUBLIC OF MAURITANIA":         "MR",
+	"ISLAMIC REPUBLIC OF PAKISTAN":           "PK",
+	"ISLE OF MAN":                            "IM",
+	"ISRAEL":                                 "IL",
+	"ITALIAN REPUBLIC":                       "IT",
+	"ITALY":                                  "IT",
+	"JAMAICA":                                "JM",
+	"JAPAN":                                  "JP",
+	"JERSEY":                                 "JE",
+	"JORDAN":                                 "JO",
+	"KAZAKHSTAN":                             "KZ",
+	"KENYA":                                  "KE",
+	"KINGDOM OF BAHRAIN":                     "BH",
+	"KINGDOM OF BELGIUM":                     "BE",
+	"KINGDOM OF BHUTAN":                      "BT",
+	"KINGDOM OF CAMBODIA":                    "KH",
+	"KINGDOM OF DENMARK":                     "DK",
+	"KINGDOM OF ESWATINI":                    "SZ",
+	"KINGDOM OF LESOTHO":                     "LS",
+	"KINGDOM OF MOROCCO":                     "MA",
+	"KINGDOM OF NORWAY":                      "NO",
+	"KINGDOM OF SAUDI ARABIA":                "SA",
+	"KINGDOM OF SPAIN":                       "ES",
+	"KINGDOM OF SWEDEN":                      "SE",
+	"KINGDOM OF THAILAND":                    "TH",
+	"KINGDOM OF THE NETHERLANDS":             "NL",
+	"KINGDOM OF TONGA":                       "TO",
+	"KIRIBATI":                               "KI",
+	"KOREA, DEMOCRATIC PEOPLE'S REPUBLIC OF": "KP",
+	"KOREA, REPUBLIC OF":                     "KR",
+	"KUWAIT":                                 "KW",
+	"KYRGYZ REPUBLIC":                        "KG",
+	"KYRGYZSTAN":                             "KG",
+	"LAO PEOPLE'S DEMOCRATIC REPUBLIC":       "LA",
+	"LAOS":                                   "LA",
+	"LATVIA":                                 "LV",
+	"LEBANESE REPUBLIC":                      "LB",
+	"LEBANON":                                "LB",
+	"LESOTHO":                                "LS",
+	"LIBERIA":                                "LR",
+	"LIBYA":                                  "LY",
+	"LIECHTENSTEIN":                          "LI",
+	"LITHUANIA":                              "LT",
+	"LUXEMBOURG":                             "LU",
+	"MACAO":                                  "MO",
+	"MACAO SPECIAL ADMINISTRATIVE REGION OF CHINA": "MO",
+	"MADAGASCAR":                      "MG",
+	"MALAWI":                          "MW",
+	"MALAYSIA":                        "MY",
+	"MALDIVES":                        "MV",
+	"MALI":                            "ML",
+	"MALTA":                           "MT",
+	"MARSHALL ISLANDS":                "MH",
+	"MARTINIQUE":                      "MQ",
+	"MAURITANIA":                      "MR",
+	"MAURITIUS":                       "MU",
+	"MAYOTTE":                         "YT",
+	"MEXICO":                          "MX",
+	"MICRONESIA, FEDERATED STATES OF": "FM",
+	"MOLDOVA":                         "MD",
+	"MOLDOVA, REPUBLIC OF":            "MD",
+	"MONACO":                          "MC",
+	"MONGOLIA":                        "MN",
+	"MONTENEGRO":                      "ME",
+	"MONTSERRAT":                      "MS",
+	"MOROCCO":                         "MA",
+	"MOZAMBIQUE":                      "MZ",
+	"MYANMAR":                         "MM",
+	"NAMIBIA":                         "NA",
+	"NAURU":                           "NR",
+	"NEPAL":                           "NP",
+	"NETHERLANDS":                     "NL",
+	"NEW CALEDONIA":                   "NC",
+	"NEW ZEALAND":                     "NZ",
+	"NICARAGUA":                       "NI",
+	"NIGER":                           "NE",
+	"NIGERIA":                         "NG",
+	"NIUE":                            "NU",
+	"NORFOLK ISLAND":                  "NF",
+	"NORTH KOREA":                     "KP",
+	"NORTH MACEDONIA":                 "MK",
+	"NORTHERN MARIANA ISLANDS":        "MP",
+	"NORWAY":                          "NO",
+	"OMAN":                            "OM",
+	"PAKISTAN":                        "PK",
+	"PALAU":                           "PW",
+	"PALESTINE, STATE OF":             "PS",
+	"PANAMA":                          "PA",
+	"PAPUA NEW GUINEA":                "PG",
+	"PARAGUAY":                        "PY",
+	"PEOPLE'S DEMOCRATIC REPUBLIC OF ALGERIA":      "DZ",
+	"PEOPLE'S REPUBLIC OF BANGLADESH":              "BD",
+	"PEOPLE'S REPUBLIC OF CHINA":                   "CN",
+	"PERU":                                         "PE",
+	"PHILIPPINES":                                  "PH",
+	"PITCAIRN":                                     "PN",
+	"PLURINATIONAL STATE OF BOLIVIA":               "BO",
+	"POLAND":                                       "PL",
+	"PORTUGAL":                                     "PT",
+	"PORTUGUESE REPUBLIC":                          "PT",
+	"PRINCIPALITY OF ANDORRA":                      "AD",
+	"PRINCIPALITY OF LIECHTENSTEIN":                "LI",
+	"PRINCIPALITY OF MONACO":                       "MC",
+	"PUERTO RICO":                                  "PR",
+	"QATAR":                                        "QA",
+	"REPUBLIC OF ALBANIA":                          "AL",
+	"REPUBLIC OF ANGOLA":                           "AO",
+	"REPUBLIC OF ARMENIA":                          "AM",
+	"REPUBLIC OF AUSTRIA":                          "AT",
+	"REPUBLIC OF AZERBAIJAN":                       "AZ",
+	"REPUBLIC OF BELARUS":                          "BY",
+	"REPUBLIC OF BENIN":                            "BJ",
+	"REPUBLIC OF BOSNIA AND HERZEGOVINA":           "BA",
+	"REPUBLIC OF BOTSWANA":                         "BW",
+	"REPUBLIC OF BULGARIA":                         "BG",
+	"REPUBLIC OF BURUNDI":                          "BI",
+	"REPUBLIC OF CABO VERDE":                       "CV",
+	"REPUBLIC OF CAMEROON":                         "CM",
+	"REPUBLIC OF CHAD":                             "TD",
+	"REPUBLIC OF CHILE":                            "CL",
+	"REPUBLIC OF COLOMBIA":                         "CO",
+	"REPUBLIC OF COSTA RICA":                       "CR",
+	"REPUBLIC OF CROATIA":                          "HR",
+	"REPUBLIC OF CUBA":                             "CU",
+	"REPUBLIC OF CYPRUS":                           "CY",
+	"REPUBLIC OF CÔTE D'IVOIRE":                    "CI",
+	"REPUBLIC OF DJIBOUTI":                         "DJ",
+	"REPUBLIC OF ECUADOR":                          "EC",
+	"REPUBLIC OF EL SALVADOR":                      "SV",
+	"REPUBLIC OF EQUATORIAL GUINEA":                "GQ",
+	"REPUBLIC OF ESTONIA":                          "EE",
+	"REPUBLIC OF FIJI":                             "FJ",
+	"REPUBLIC OF FINLAND":                          "FI",
+	"REPUBLIC OF GHANA":                            "GH",
+	"REPUBLIC OF GUATEMALA":                        "GT",
+	"REPUBLIC OF GUINEA":                           "GN",
+	"REPUBLIC OF GUINEA-BISSAU":                    "GW",
+	"REPUBLIC OF GUYANA":                           "GY",
+	"REPUBLIC OF HAITI":                            "HT",
+	"REPUBLIC OF HONDURAS":                         "HN",
+	"REPUBLIC OF ICELAND":                          "IS",
+	"REPUBLIC OF INDIA":                            "IN",
+	"REPUBLIC OF INDONESIA":                        "ID",
+	"REPUBLIC OF IRAQ":                             "IQ",
+	"REPUBLIC OF KAZAKHSTAN":                       "KZ",
+	"REPUBLIC OF KENYA":                            "KE",
+	"REPUBLIC OF KIRIBATI":                         "KI",
+	"REPUBLIC OF LATVIA":                           "LV",
+	"REPUBLIC OF LIBERIA":                          "LR",
+	"REPUBLIC OF LITHUANIA":                        "LT",
+	"REPUBLIC OF MADAGASCAR":                       "MG",
+	"REPUBLIC OF MALAWI":                           "MW",
+	"REPUBLIC OF MALDIVES":                         "MV",
+	"REPUBLIC OF MALI":                             "ML",
+	"REPUBLIC OF MALTA":                            "MT",
+	"REPUBLIC OF MAURITIUS":                        "MU",
+	"REPUBLIC OF MOLDOVA":                          "MD",
+	"REPUBLIC OF MOZAMBIQUE":                       "MZ",
+	"REPUBLIC OF MYANMAR":                          "MM",
+	"REPUBLIC OF NAMIBIA":                          "NA",
+	"REPUBLIC OF NAURU":                            "NR",
+	"REPUBLIC OF NICARAGUA":                        "NI",
+	"REPUBLIC OF NORTH MACEDONIA":                  "MK",
+	"REPUBLIC OF PALAU":                            "PW",
+	"REPUBLIC OF PANAMA":                           "PA",
+	"REPUBLIC OF PARAGUAY":                         "PY",
+	"REPUBLIC OF PERU":                             "PE",
+	"REPUBLIC OF POLAND":                           "PL",
+	"REPUBLIC OF SAN MARINO":                       "SM",
+	"REPUBLIC OF SENEGAL":                          "SN",
+	"REPUBLIC OF SERBIA":                           "RS",
+	"REPUBLIC OF SEYCHELLES":                       "SC",
+	"REPUBLIC OF SIERRA LEONE":                     "SL",
+	"REPUBLIC OF SINGAPORE":                        "SG",
+	"REPUBLIC OF SLOVENIA":                         "SI",
+	"REPUBLIC OF SOUTH AFRICA":                     "ZA",
+	"REPUBLIC OF SOUTH SUDAN":                      "SS",
+	"REPUBLIC OF SURINAME":                         "SR",
+	"REPUBLIC OF TAJIKISTAN":                       "TJ",
+	"REPUBLIC OF THE CONGO":                        "CG",
+	"REPUBLIC OF THE GAMBIA":                       "GM",
+	"REPUBLIC OF THE MARSHALL ISLANDS":             "MH",
+	"REPUBLIC OF THE NIGER":                        "NE",
+	"REPUBLIC OF THE PHILIPPINES":                  "PH",
+	"REPUBLIC OF THE SUDAN":                        "SD",
+	"REPUBLIC OF TRINIDAD AND TOBAGO":              "TT",
+	"REPUBLIC OF TUNISIA":                          "TN",
+	"REPUBLIC OF TÜRKIYE":                          "TR",
+	"REPUBLIC OF UGANDA":                           "UG",
+	"REPUBLIC OF UZBEKISTAN":                       "UZ",
+	"REPUBLIC OF VANUATU":                          "VU",
+	"REPUBLIC OF YEMEN":                            "YE",
+	"REPUBLIC OF ZAMBIA":                           "ZM",
+	"REPUBLIC OF ZIMBABWE":                         "ZW",
+	"ROMANIA":                                      "RO",
+	"RUSSIAN FEDERATION":                           "RU",
+	"RWANDA":                                       "RW",
+	"RWANDESE REPUBLIC":                            "RW",
+	"RÉUNION":                                      "RE",
+	"SAINT BARTHÉLEMY":                             "BL",
+	"SAINT HELENA, ASCENSION AND TRISTAN DA CUNHA": "SH",
+	"SAINT KITTS AND NEVIS":                        "KN",
+	"SAINT LUCIA":                                  "LC",
+	"SAINT MARTIN (FRENCH PART)":                   "MF",
+	"SAINT PIERRE AND MIQUELON":                    "PM",
+	"SAINT VINCENT AND THE GRENADINES":             "VC",
+	"SAMOA":                                        "WS",
+	"SAN MARINO":                                   "SM",
+	"SAO TOME AND PRINCIPE":                        "ST",
+	"SAUDI ARABIA":                                 "SA",
+	"SENEGAL":                                      "SN",
+	"SERBIA":                                       "RS",
+	"SEYCHELLES":                                   "SC",
+	"SIERRA LEONE":                                 "SL",
+	"SINGAPORE":                                    "SG",
+	"SINT MAARTEN (DUTCH PART)":                    "SX",
+	"SLOVAK REPUBLIC":                              "SK",
+	"SLOVAKIA":                                     "SK",
+	"SLOVENIA":                                     "SI",
+	"SOCIALIST REPUBLIC OF VIET NAM":               "VN",
+	"SOLOMON ISLANDS":                              "SB",
+	"SOMALIA":                                      "SO",
+	"SOUTH AFRICA":                                 "ZA",
+	"SOUTH GEORGIA AND THE SOUTH SANDWICH ISLANDS": "GS",
+	"SOUTH KOREA":                                  "KR",
+	"SOUTH SUDAN":                                  "SS",
+	"SPAIN":                                        "ES",
+	"SRI LANKA":                                    "LK",
+	"STATE OF ISRAEL":                              "IL",
+	"STATE OF KUWAIT":                              "KW",
+	"STATE OF QATAR":                               "QA",
+	"SUDAN":                                        "SD",
+	"SULTANATE OF OMAN":                            "OM",
+	"SURINAME":                                     "SR",
+	"SVALBARD AND JAN MAYEN":                       "SJ",
+	"SWEDEN":                                       "SE",
+	"SWISS CONFEDERATION":                          "CH",
+	"SWITZERLAND":                                  "CH",
+	"SYRIA":                                        "SY",
+	"SYRIAN ARAB REPUBLIC":                         "SY",
+	"TAIWAN":                                       "TW",
+	"TAIWAN, PROVINCE OF CHINA":                    "TW",
+	"TAJIKISTAN":                                   "TJ",
+	"TANZANIA":                                     "TZ",
+	"TANZANIA, UNITED REPUBLIC OF":                 "TZ",
+	"THAILAND":                                     "TH",
+	"THE STATE OF ERITREA":                         "ER",
+	"THE STATE OF PALESTINE":                       "PS",
+	"TIMOR-LESTE":                                  "TL",
+	"TOGO":                                         "TG",
+	"TOGOLESE REPUBLIC":                            "TG",
+	"TOKELAU":                                      "TK",
+	"TONGA":                                        "TO",
+	"TRINIDAD AND TOBAGO":                          "TT",
+	"TUNISIA":                                      "TN",
+	"TURKMENISTAN":                                 "TM",
+	"TURKS AND CAICOS ISLANDS":                     "TC",
+	"TUVALU":                                       "TV",
+	"TÜRKIYE":                                      "TR",
+	"UGANDA":                                       "UG",
+	"UKRAINE":                                      "UA",
+	"UNION OF THE COMOROS":                         "KM",
+	"UNITED ARAB EMIRATES":                         "AE",
+	"UNITED KINGDOM":                               "GB",
+	"UNITED KINGDOM OF GREAT BRITAIN AND NORTHERN IRELAND": "GB",
+	"UNITED MEXICAN STATES":                                "MX",
+	"UNITED REPUBLIC OF TANZANIA":                          "TZ",
+	"UNITED STATES":                                        "US",
+	"UNITED STATES MINOR OUTLYING ISLANDS":                 "UM",
+	"UNITED STATES OF AMERICA":                             "US",
+	"URUGUAY":                                              "UY",
+	"UZBEKISTAN":                                           "UZ",
+	"VANUATU":                                              "VU",
+	"VENEZUELA":                                            "VE",
+	"VENEZUELA, BOLIVARIAN REPUBLIC OF":                    "VE",
+	"VIET NAM":                                             "VN",
+	"VIETNAM":                                              "VN",
+	"VIRGIN ISLANDS OF THE UNITED STATES":                  "VI",
+	"VIRGIN ISLANDS, BRITISH":                              "VG",
+	"VIRGIN ISLANDS, U.S.":                                 "VI",
+	"WALLIS AND FUTUNA":                                    "WF",
+	"WESTERN SAHARA":                                       "EH",
+	"YEMEN":                                                "YE",
+	"ZAMBIA":                                               "ZM",
+	"ZIMBABWE":                                             "ZW",
+	"ÅLAND ISLANDS":                                        "AX",
+}
+
+// countryAliases maps former ISO names and other spellings found in older
+// registry records to alpha-2 codes.
+var countryAliases = map[string]string{
+	"BRITAIN":                "GB",
+	"CAPE VERDE":             "CV",
+	"ENGLAND":                "GB",
+	"GREAT BRITAIN":          "GB",
+	"HOLLAND":                "NL",
+	"KOREA":                  "KR",
+	"KOREA (SOUTH)":          "KR",
+	"LIBYAN ARAB JAMAHIRIYA": "LY",
+	"MACEDONIA":              "MK",
+	"MACEDONIA, THE FORMER YUGOSLAV REPUBLIC OF": "MK",
+	"PALESTINIAN TERRITORY, OCCUPIED":            "PS",
+	"P.R. CHINA":                                 "CN",
+	"PRC":                                        "CN",
+	"REPUBLIC OF KOREA":                          "KR",
+	"SCOTLAND":                                   "GB",
+	"SWAZILAND":                                  "SZ",
+	"TURKEY":                                     "TR",
+	"U.K.":                                       "GB",
+	"U.S.A.":                                     "US",
+	"UK":                                         "GB",
+	"USA":                                        "US",
+	"WALES":                                      "GB",
+}
+
+// normalizeCountry converts a country code or name, as found in registration
+// records of any era, to an ISO 3166-1 alpha-2 code. It returns the trimmed
+// input and false when the country cannot be resolved.
+func normalizeCountry(country string) (string, bool) {
+	country = strings.Join(strings.Fields(country), " ")
+	if country == "" {
+		return "", true
+	}
+	upper := strings.ToUpper(country)
+	name := strings.TrimSuffix(upper, ".")
+	if _, ok := ieee.Countries[name]; ok {
+		return name, true
+	}
+	// Names such as "VIRGIN ISLANDS, U.S." end with a period of their own
+	for _, key := range []string{upper, name} {
+		if code, ok := countryNames[key]; ok {
+			return code, true
+		}
+		if code, ok := countryAliases[key]; ok {
+			return code, true
+		}
+	}
+	return country, false
+}
+
+// unresolvedCountry counts the records with a country that normalizeCountry
+// could not resolve.
+type unresolvedCountry struct {
+	Country string
+	Count   int
+	Example string // Prefix of one of the records
+}
+
+// normalizeCountries rewrites the country of every registration entry as an
+// ISO 3166-1 alpha-2 code. Entries that cannot be resolved keep their country
+// and are returned, most frequent first, along with the number of entries
+// that were changed.
+func normalizeCountries(data MACData) (int, []unresolvedCountry) {
+	changed := 0
+	unresolved := make(map[string]*unresolvedCountry)
+	for prefix, entries := range data {
+		for i := range entries {
+			code, ok := normalizeCountry(entries[i].Country)
+			if !ok {
+				u := unresolved[code]
+				if u == nil {
+					u = &unresolvedCountry{Country: code}
+					unresolved[code] = u
+				}
+				u.Count++
+				if u.Example == "" || prefix < u.Example {
+					u.Example = prefix
+				}
+			}
+			if code != entries[i].Country {
+				entries[i].Country = code
+				changed++
+			}
+		}
+	}
+
+	res := make([]unresolvedCountry, 0, len(unresolved))
+	for _, u := range unresolved {
+		res = append(res, *u)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Country < res[j].Country
+	})
+	return changed, res
+}
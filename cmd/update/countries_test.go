@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/runZeroInc/mac-tracker/internal/ieee"
+)
+
+func TestNormalizeCountry(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"CA", "CA", true},
+		{"ca", "CA", true},
+		{"CANADA", "CA", true},
+		{"UNITED STATES", "US", true},
+		{"KOREA, REPUBLIC OF", "KR", true},
+		{"TAIWAN, PROVINCE OF CHINA", "TW", true},
+		{"VIET NAM", "VN", true},
+		{"Czech Republic", "CZ", true},
+		{"TURKEY", "TR", true},
+		{"U.K.", "GB", true},
+		{"Virgin Islands, U.S.", "VI", true},
+		{"BOLIVIA, PLURINATIONAL STATE OF", "BO", true},
+		{"  UNITED\tKINGDOM ", "GB", true},
+		{"", "", true},
+		{"NETHERLANDS ANTILLES", "NETHERLANDS ANTILLES", false},
+		{"XX", "XX", false},
+	}
+	for _, test := range tests {
+		got, ok := normalizeCountry(test.input)
+		if got != test.want || ok != test.ok {
+			t.Errorf("normalizeCountry(%q) = %q, %v, want %q, %v", test.input, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestNormalizeCountries(t *testing.T) {
+	data := MACData{
+		"000e02000000/24": {
+			{Date: "2003-09-08", Type: "add", Country: "CANADA", Source: "wireshark.org"},
+			{Date: "2015-08-27", Type: "change", Country: "CA"},
+		},
+		"70b3d5000000/24": {
+			{Date: "2014-01-09", Type: "add", Country: "UNITED STATES"},
+		},
+		"00a054000000/24": {
+			{Date: "2003-09-08", Type: "add", Country: "NETHERLANDS ANTILLES"},
+		},
+		"0000f0000000/24": {
+			{Date: "2003-09-08", Type: "add", Country: "NETHERLANDS ANTILLES"},
+		},
+	}
+
+	changed, unresolved := normalizeCountries(data)
+	if changed != 2 {
+		t.Errorf("changed %d countries, want 2", changed)
+	}
+	if got := data["000e02000000/24"][0].Country; got != "CA" {
+		t.Errorf("CANADA normalized to %q, want CA", got)
+	}
+	if got := data["70b3d5000000/24"][0].Country; got != "US" {
+		t.Errorf("UNITED STATES normalized to %q, want US", got)
+	}
+	if got := data["00a054000000/24"][0].Country; got != "NETHERLANDS ANTILLES" {
+		t.Errorf("unresolved country rewritten to %q", got)
+	}
+	want := unresolvedCountry{Country: "NETHERLANDS ANTILLES", Count: 2, Example: "0000f0000000/24"}
+	if len(unresolved) != 1 || unresolved[0] != want {
+		t.Errorf("unresolved = %+v, want [%+v]", unresolved, want)
+	}
+}
+
+// TestCountryNames checks that every name resolves to a known code.
+func TestCountryNames(t *testing.T) {
+	for _, names := range []map[string]string{countryNames, countryAliases} {
+		for name, code := range names {
+			if _, ok := ieee.Countries[code]; !ok {
+				t.Errorf("%q maps to unknown code %q", name, code)
+			}
+		}
+	}
+}
+
+// TestCountryMapsDisjoint checks that each spelling is listed only once.
+func TestCountryMapsDisjoint(t *testing.T) {
+	for name := range countryAliases {
+		if _, ok := countryNames[name]; ok {
+			t.Errorf("%q is in both countryNames and countryAliases", name)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fetchStateFile is the sidecar an HTTPSource keeps in its cache directory.
+const fetchStateFile = "fetch.json"
+
+// fetchRecord is what an HTTPSource remembers about a registry file between
+// runs.
+type fetchRecord struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Status       int       `json:"status"`  // Status of the last response
+	Checked      time.Time `json:"checked"` // When the file was last requested
+	Changed      time.Time `json:"changed"` // When new content was last received
+	Size         int       `json:"size"`
+	SHA256       string    `json:"sha256"` // Checksum of the cached copy
+}
+
+// fetchState holds the fetch records of a cache directory by file name.
+type fetchState map[string]*fetchRecord
+
+// loadFetchState reads a sidecar file, returning an empty state when there
+// is none yet.
+func loadFetchState(path string) (fetchState, error) {
+	state := make(fetchState)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// save writes the state to a sidecar file.
+func (s fetchState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// cached returns the copy of a file in dir when it is the one the state
+// describes for url, so it may be revalidated instead of downloaded again.
+func (s fetchState) cached(dir, name, url string) []byte {
+	rec := s[name]
+	if rec == nil || rec.URL != url || (rec.ETag == "" && rec.LastModified == "") {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != rec.SHA256 {
+		return nil
+	}
+	return data
+}
+
+// record updates the state after a successful fetch of data.
+func (s fetchState) record(name, url string, res *fetchResult, data []byte) {
+	now := time.Now().UTC().Truncate(time.Second)
+	rec := s[name]
+	if rec == nil {
+		rec = &fetchRecord{}
+		s[name] = rec
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+	if res.status == http.StatusOK && (checksum != rec.SHA256 || rec.Changed.IsZero()) {
+		rec.Changed = now
+	}
+	// A 304 may omit the validators, which then stay the same
+	if res.status == http.StatusOK || res.etag != "" {
+		rec.ETag = res.etag
+	}
+	if res.status == http.StatusOK || res.lastModified != "" {
+		rec.LastModified = res.lastModified
+	}
+	rec.URL = url
+	rec.Status = res.status
+	rec.Checked = now
+	rec.Size = len(data)
+	rec.SHA256 = checksum
+}
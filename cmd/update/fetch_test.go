@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPSourceConditional(t *testing.T) {
+	version, downloads := 1, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != UserAgent {
+			t.Errorf("request with User-Agent %q", ua)
+		}
+		etag := fmt.Sprintf(`"v%d"`, version)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 26 Jan 2026 00:00:00 GMT")
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		fmt.Fprintf(w, "Registry,Assignment\nMA-L,00000%d\n", version)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	src := &HTTPSource{BaseURL: srv.URL, Client: srv.Client(), CacheDir: dir}
+	fetch := func() string {
+		t.Helper()
+		data, err := src.Fetch(context.Background(), "oui.csv")
+		if err != nil {
+			t.Fatalf("Fetch: %v", err)
+		}
+		// Store and commit the file as the updater does
+		if err := os.WriteFile(filepath.Join(dir, "oui.csv"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := src.Commit("oui.csv"); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		return string(data)
+	}
+
+	first := fetch()
+	state, err := loadFetchState(filepath.Join(dir, fetchStateFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := state["oui.csv"]
+	if rec == nil || rec.ETag != `"v1"` || rec.LastModified == "" || rec.Status != http.StatusOK || rec.Size != len(first) {
+		t.Fatalf("fetch record after download = %+v", rec)
+	}
+	changed := rec.Changed
+
+	// Unchanged files are revalidated, not downloaded
+	if got := fetch(); got != first || downloads != 1 {
+		t.Errorf("revalidated fetch returned %q after %d downloads", got, downloads)
+	}
+	state, _ = loadFetchState(filepath.Join(dir, fetchStateFile))
+	if rec := state["oui.csv"]; rec.Status != http.StatusNotModified || !rec.Changed.Equal(changed) {
+		t.Errorf("fetch record after revalidation = %+v", rec)
+	}
+
+	version = 2
+	if got := fetch(); got == first || downloads != 2 {
+		t.Errorf("fetch of a new version returned %q after %d downloads", got, downloads)
+	}
+
+	// A cached copy that no longer matches the sidecar is downloaded again
+	if err := os.WriteFile(filepath.Join(dir, "oui.csv"), []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := fetch(); got == "edited" || downloads != 3 {
+		t.Errorf("fetch over an edited copy returned %q after %d downloads", got, downloads)
+	}
+}
+
+func TestFetchIEEETruncated(t *testing.T) {
+	full := "Registry,Assignment,Organization Name,Organization Address\n" +
+		"MA-L,000001,One,Here\nMA-L,000002,Two,Here\nMA-L,000003,Three,Here\n"
+	body, etag, conditional := full, `"v1"`, ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = r.Header.Get("If-None-Match")
+		w.Header().Set("ETag", etag)
+		if conditional == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	dataDir := filepath.Join(t.TempDir(), "data")
+	ieeeDir := filepath.Join(dataDir, "ieee")
+	if err := os.MkdirAll(ieeeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	info := &MACUpdate{dir: filepath.Dir(dataDir), dataDir: dataDir}
+	src := &HTTPSource{BaseURL: srv.URL, Client: srv.Client(), CacheDir: ieeeDir}
+	file := ieeeFile{"oui.csv", "oui/oui.csv", 4} // Counting the header row
+
+	if _, err := fetchIEEE(info, src, file); err != nil {
+		t.Fatalf("fetchIEEE: %v", err)
+	}
+
+	// A truncated 200 response is neither stored nor recorded
+	body, etag = full[:strings.LastIndex(full[:len(full)-1], "\n")+1], `"v2"`
+	if _, err := fetchIEEE(info, src, file); err == nil {
+		t.Fatal("fetchIEEE accepted a truncated file")
+	}
+	if data, _ := os.ReadFile(filepath.Join(ieeeDir, "oui.csv")); string(data) != full {
+		t.Errorf("truncated download replaced oui.csv with %q", data)
+	}
+	state, err := loadFetchState(filepath.Join(ieeeDir, fetchStateFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := state["oui.csv"]; rec == nil || rec.ETag != `"v1"` {
+		t.Errorf("fetch record after a truncated download = %+v", rec)
+	}
+
+	// The next run asks for the new version again rather than keeping the bad copy
+	body = full
+	if _, err := fetchIEEE(info, src, file); err != nil || conditional != `"v1"` {
+		t.Errorf("fetchIEEE after a truncated download sent If-None-Match %q: %v", conditional, err)
+	}
+}
+
+func TestHTTPSourceBackoff(t *testing.T) {
+	attempts, down := 0, false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch {
+		case down:
+			http.Error(w, "down", http.StatusBadGateway)
+		case attempts == 1:
+			w.Header().Set("Retry-After", "3")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		case attempts <= 3:
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	src := &HTTPSource{
+		BaseURL:    srv.URL,
+		Client:     srv.Client(),
+		Retries:    5,
+		Backoff:    100 * time.Millisecond,
+		MaxBackoff: 10 * time.Second,
+		wait: func(ctx context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		},
+	}
+	if _, err := src.Fetch(context.Background(), "oui.csv"); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(delays) != 3 {
+		t.Fatalf("waited %d times, want 3", len(delays))
+	}
+	if delays[0] != 3*time.Second {
+		t.Errorf("delay after Retry-After: 3 = %s", delays[0])
+	}
+	// Jittered delays of 200ms and 400ms
+	for i, base := range []time.Duration{200 * time.Millisecond, 400 * time.Millisecond} {
+		if d := delays[i+1]; d < base/2 || d >= base {
+			t.Errorf("delay %d = %s, want in [%s, %s)", i+2, d, base/2, base)
+		}
+	}
+
+	// Retries give up with the last error
+	down, delays = true, nil
+	src.Retries = 2
+	if _, err := src.Fetch(context.Background(), "oui.csv"); err == nil || len(delays) != 2 {
+		t.Errorf("Fetch after %d waits = %v, want an error after 2", len(delays), err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 26, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{"Mon, 26 Jan 2026 00:00:30 GMT", 30 * time.Second},
+		{"Sun, 25 Jan 2026 00:00:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, test := range tests {
+		if got := parseRetryAfter(test.input, now); got != test.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", test.input, got, test.want)
+		}
+	}
+}
@@ -1,12 +1,8 @@
 package main
 
 import (
-	"encoding/csv"
 	"encoding/json"
-	"fmt"
-	"os"
 	"path/filepath"
-	"strings"
 	"testing"
 )
 
@@ -32,7 +28,8 @@ func TestFullUpdate(t *testing.T) {
 	oldCount := len(info.data)
 
 	// Load from local IEEE files instead of downloading
-	if err := loadIEEEFromLocal(info); err != nil {
+	src := &DirSource{Dir: filepath.Join(info.dir, "data", "ieee")}
+	if err := loadIEEE(info, src); err != nil {
 		t.Fatalf("Failed to load IEEE data: %v", err)
 	}
 
@@ -50,77 +47,6 @@ func TestFullUpdate(t *testing.T) {
 	}
 }
 
-func loadIEEEFromLocal(info *MACUpdate) error {
-	ieeeFiles := []struct {
-		filename   string
-		minRecords int
-	}{
-		{"oui.csv", 37585},
-		{"cid.csv", 200},
-		{"iab.csv", 4576},
-		{"mam.csv", 5890},
-		{"oui36.csv", 6560},
-	}
-
-	for _, fileInfo := range ieeeFiles {
-		processed := make(map[string]bool)
-		path := filepath.Join(info.dir, "data", "ieee", fileInfo.filename)
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", path, err)
-		}
-
-		// Parse CSV
-		dataStr := strings.TrimRight(string(data), "\n\r")
-		reader := csv.NewReader(strings.NewReader(dataStr))
-		reader.LazyQuotes = true
-
-		records, err := reader.ReadAll()
-		if err != nil {
-			return fmt.Errorf("failed to parse CSV %s: %w", path, err)
-		}
-
-		if len(records) < fileInfo.minRecords {
-			return fmt.Errorf("file %s only has %d records (wanted >= %d)", fileInfo.filename, len(records), fileInfo.minRecords)
-		}
-
-		for _, rec := range records {
-			if len(rec) < 4 {
-				continue
-			}
-
-			// Skip header rows
-			if strings.HasPrefix(rec[0], "Registry") {
-				continue
-			}
-
-			addrBase := rec[1]
-			addrMask := int((float64(len(addrBase)) / 2.0) * 8)
-			// Pad with zeros to 12 characters
-			for len(addrBase) < 12 {
-				addrBase += "0"
-			}
-			addr := fmt.Sprintf("%s/%d", strings.ToLower(addrBase), addrMask)
-
-			// Skip duplicates
-			if processed[addr] {
-				continue
-			}
-
-			// Replace literal \n with actual newlines
-			address := strings.ReplaceAll(rec[3], "\\n", "\n")
-
-			sourceName := "ieee-" + fileInfo.filename
-			updateRegistration(info, addr, info.today, rec[2], address, sourceName)
-			updateAge(info, addr, info.today, sourceName)
-			processed[addr] = true
-		}
-	}
-
-	return nil
-}
-
 func TestJSONOutput(t *testing.T) {
 	// Test that we can marshal the data structure
 	testData := MACData{
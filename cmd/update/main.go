@@ -3,15 +3,14 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
-	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -21,16 +20,18 @@ import (
 	"unicode/utf8"
 
 	mactracker "github.com/runZeroInc/mac-tracker"
+	"github.com/runZeroInc/mac-tracker/internal/ieee"
 )
 
 // RegistrationEntry represents a single MAC address registration or change event
 type RegistrationEntry struct {
-	Date    string `json:"d"`
-	Type    string `json:"t"`
-	Source  string `json:"s"`
-	Address string `json:"a"`
-	Country string `json:"c"`
-	Org     string `json:"o"`
+	Date     string `json:"d"`
+	Type     string `json:"t"`
+	Source   string `json:"s"`
+	Address  string `json:"a"`
+	Country  string `json:"c"`
+	Org      string `json:"o"`
+	Registry string `json:"r,omitempty"`
 }
 
 // MACData stores the full registration history
@@ -39,32 +40,208 @@ type MACData map[string][]RegistrationEntry
 // MACAges stores the earliest registration for each MAC
 type MACAges map[string][2]string // [date, source]
 
-const MaxRetries = 30
+const MaxRetries = 8
 
 type MACUpdate struct {
-	ages  MACAges
-	data  MACData
-	today string
-	dir   string
-	now   string
+	ages    MACAges
+	data    MACData
+	today   string
+	dir     string // Base directory, where oui_table.bin.gz is written
+	dataDir string // Directory holding macs.json and the registry files; defaults to dir/data
+	now     string
+	files   []ieeeFile // Registry files to read; defaults to ieeeFiles
+	dryRun  bool       // Report changes without writing files
+	changes *Changelog // Changes merged in this run
 }
 
+// dataPath returns the path of a file in the data directory.
+func (info *MACUpdate) dataPath(elem ...string) string {
+	dir := info.dataDir
+	if dir == "" {
+		dir = filepath.Join(info.dir, "data")
+	}
+	return filepath.Join(append([]string{dir}, elem...)...)
+}
+
+// changelog returns the changes merged in this run.
+func (info *MACUpdate) changelog() *Changelog {
+	if info.changes == nil {
+		info.changes = &Changelog{Date: info.today}
+	}
+	return info.changes
+}
+
+// registryFiles returns the registry files the update reads.
+func (info *MACUpdate) registryFiles() []ieeeFile {
+	if info.files == nil {
+		return ieeeFiles
+	}
+	return info.files
+}
+
+const usage = `usage: update [flags] [fetch | merge | build]
+
+With no command, update fetches the IEEE registry files, merges them into
+the registration history and rebuilds oui_table.bin.gz. The commands run
+a single stage:
+
+  fetch  download the registry files into the data directory
+  merge  merge the registry files in the data directory into macs.json
+         and mac-ages.csv
+  build  rebuild oui_table.bin.gz from macs.json
+
+Flags:
+`
+
 func main() {
+	source := flag.String("source", "ieee", `where to fetch the IEEE registry files: "ieee", a mirror's base URL, or a directory such as data/ieee`)
+	date := flag.String("date", "", "record changes under this date (YYYY-MM-DD) instead of today, to backfill a missed run; must not be before the newest recorded entry")
+	dataDir := flag.String("data-dir", "", "directory holding macs.json, mac-ages.csv and the ieee registry files; oui_table.bin.gz is written to its parent (default: the repository's data directory)")
+	dryRun := flag.Bool("dry-run", false, "report changes without writing any files")
+	sources := flag.String("sources", "", "comma-separated registries to read, by file (oui,cid,iab,mam,oui36) or registry name (MA-L,CID,IAB,MA-M,MA-S); default all")
+	noBin := flag.Bool("no-bin", false, "don't rebuild oui_table.bin.gz")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	command := flag.Arg(0)
+	switch {
+	case flag.NArg() > 1:
+		flag.Usage()
+		os.Exit(2)
+	case command != "" && command != "fetch" && command != "merge" && command != "build":
+		log.Printf("Unknown command %q", command)
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	info := &MACUpdate{
-		ages:  make(MACAges),
-		data:  make(MACData),
-		today: time.Now().Format("2006-01-02"),
-		now:   time.Now().String(),
-		dir:   getBaseDirectory(),
+		ages:   make(MACAges),
+		data:   make(MACData),
+		today:  time.Now().Format("2006-01-02"),
+		now:    time.Now().String(),
+		dryRun: *dryRun,
+	}
+	if *date != "" {
+		if _, err := time.Parse("2006-01-02", *date); err != nil {
+			log.Printf("Bad -date %q (want YYYY-MM-DD)", *date)
+			os.Exit(2)
+		}
+		info.today = *date
+	}
+	if *dataDir != "" {
+		abs, err := filepath.Abs(*dataDir)
+		if err != nil {
+			log.Printf("Bad -data-dir: %v", err)
+			os.Exit(2)
+		}
+		info.dataDir, info.dir = abs, filepath.Dir(abs)
+	} else {
+		info.dir = getBaseDirectory()
 	}
+	files, err := selectRegistryFiles(*sources)
+	if err != nil {
+		log.Printf("Bad -sources: %v", err)
+		os.Exit(2)
+	}
+	info.files = files
+
+	// A dry run neither caches the downloads nor records fetch state
+	cacheDir := info.dataPath("ieee")
+	if info.dryRun {
+		cacheDir = ""
+	}
+	src, err := parseSource(*source, cacheDir)
+	if err != nil {
+		log.Printf("Bad -source: %v", err)
+		os.Exit(2)
+	}
+
+	log.Printf("Starting update for %s in %s", info.today, info.dataPath())
+	if info.dryRun {
+		log.Printf("Dry run: no files will be written")
+	}
+
+	switch command {
+	case "fetch":
+		err = runFetch(info, src)
+	case "merge":
+		err = runMerge(info, &DirSource{Dir: info.dataPath("ieee")})
+	case "build":
+		err = runBuild(info)
+	default:
+		err = runMerge(info, src)
+		if err == nil && !*noBin {
+			writeOUIBin(info)
+		}
+	}
+	if err != nil {
+		log.Printf("Update failed: %v", err)
+		os.Exit(1)
+	}
+}
 
-	log.Printf("Starting update for %s in %s", info.today, info.dir)
+// selectRegistryFiles returns the registry files named in a comma-separated
+// list of file names, with or without ".csv", or registry names.
+func selectRegistryFiles(list string) ([]ieeeFile, error) {
+	if strings.TrimSpace(list) == "" {
+		return ieeeFiles, nil
+	}
+	var files []ieeeFile
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, f := range ieeeFiles {
+			if strings.EqualFold(name, f.name) || strings.EqualFold(name, strings.TrimSuffix(f.name, ".csv")) ||
+				strings.EqualFold(name, ieee.SourceRegistries["ieee-"+f.name]) {
+				found = true
+				if !seen[f.name] {
+					seen[f.name] = true
+					files = append(files, f)
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown registry %q", name)
+		}
+	}
+	return files, nil
+}
+
+// runFetch downloads the registry files into the data directory.
+func runFetch(info *MACUpdate, src Source) error {
+	log.Printf("Fetching the IEEE registry files from %s", src)
+	for _, file := range info.registryFiles() {
+		records, err := fetchIEEE(info, src, file)
+		if err != nil {
+			return err
+		}
+		log.Printf("Fetched %s: %d records", file.name, len(records))
+	}
+	return nil
+}
 
+// runMerge merges the registry files from src into the registration history
+// and writes the results.
+func runMerge(info *MACUpdate, src Source) error {
 	// Load current dataset
 	log.Printf("Loading current dataset")
 	if err := loadCurrent(info); err != nil {
-		log.Printf("Failed to load current dataset: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("load current dataset: %w", err)
+	}
+	if err := checkMergeDate(info); err != nil {
+		return err
+	}
+
+	// Older records name the country in full, such as "CANADA"
+	log.Printf("Normalizing country names")
+	changed, unresolved := normalizeCountries(info.data)
+	log.Printf("Normalized %d countries", changed)
+	for _, u := range unresolved {
+		log.Printf("Unresolved country %q in %d records (e.g. %s)", u.Country, u.Count, u.Example)
 	}
 
 	// Calculate MAC ages based on the full registration data
@@ -83,42 +260,46 @@ func main() {
 		info.ages[addr] = [2]string{earliest.Date, earliest.Source}
 	}
 
-	// Load IEEE URLs
-	log.Printf("Loading the IEEE URLs")
+	// Load IEEE registry files
+	log.Printf("Loading the IEEE registry files from %s", src)
 	oldCount := len(info.data)
-	if err := loadIEEEURLs(info); err != nil {
-		log.Printf("Failed to load IEEE URLs: %v", err)
-		os.Exit(1)
+	if err := loadIEEE(info, src); err != nil {
+		return fmt.Errorf("load IEEE registry files: %w", err)
 	}
 	newCount := len(info.data)
+	log.Printf("Changes: %s", info.changelog().Summary())
+
+	if info.dryRun {
+		log.Printf("Dry run: not writing results for %d entries (%d -> %d)", len(info.data), oldCount, newCount)
+		return info.changelog().WriteMarkdown(os.Stdout)
+	}
 
 	// Write results
 	log.Printf("Writing results for %d entries (%d -> %d)", len(info.data), oldCount, newCount)
 	if err := writeResults(info); err != nil {
-		log.Printf("Failed to write results: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("write results: %w", err)
+	}
+	if err := writeChangelog(info); err != nil {
+		return fmt.Errorf("write changelog: %w", err)
 	}
+	return nil
 }
 
-var countryRegex = regexp.MustCompile(`\b([A-Z]{2})\b`)
+// runBuild rebuilds the binary OUI table from the registration history.
+func runBuild(info *MACUpdate) error {
+	log.Printf("Loading current dataset")
+	if err := loadCurrent(info); err != nil {
+		return fmt.Errorf("load current dataset: %w", err)
+	}
+	normalizeCountries(info.data)
+	writeOUIBin(info)
+	return nil
+}
 
+// countryFromAddress returns the ISO 3166-1 alpha-2 country of an IEEE
+// organization address, or an empty string when it has none.
 func countryFromAddress(address string) string {
-	if len(address) == 0 {
-		return ""
-	}
-	// Split by whitespace and find the last 2-letter uppercase country code
-	parts := regexp.MustCompile(`\s+`).Split(address, -1)
-	var c string
-	for i := len(parts) - 1; i >= 0; i-- {
-		if matched := countryRegex.MatchString(parts[i]); matched {
-			c = parts[i]
-			break
-		}
-	}
-	if c == "" || len(c) != 2 {
-		return ""
-	}
-	return c
+	return ieee.ParseAddress(address).Country
 }
 
 func mashEncoding(str string) string {
@@ -156,39 +337,112 @@ func squashCosmeticChanges(str string) string {
 	return strings.ToLower(strings.TrimSpace(mashEncoding(str)))
 }
 
-func updateRegistration(info *MACUpdate, addr, date, org, address, source string) {
+func updateRegistration(info *MACUpdate, addr, date, org, address, source, registry string) {
 	country := countryFromAddress(address)
+	changes := info.changelog()
 
 	if _, exists := info.data[addr]; !exists {
 		info.data[addr] = []RegistrationEntry{
 			{
-				Date:    date,
-				Type:    "add",
-				Source:  source,
-				Address: mashEncoding(address),
-				Country: country,
-				Org:     mashEncoding(org),
+				Date:     date,
+				Type:     "add",
+				Source:   source,
+				Address:  mashEncoding(address),
+				Country:  country,
+				Org:      mashEncoding(org),
+				Registry: registry,
 			},
 		}
+		changes.Added = append(changes.Added, PrefixChange{
+			Prefix:   addr,
+			Registry: registry,
+			Org:      mashEncoding(org),
+			Address:  mashEncoding(address),
+			Country:  country,
+		})
+		return
+	}
+
+	lastEntry := info.data[addr][len(info.data[addr])-1]
+	if lastEntry.Type == "remove" {
+		info.data[addr] = append(info.data[addr], RegistrationEntry{
+			Date:     date,
+			Type:     "re-add",
+			Source:   source,
+			Address:  mashEncoding(address),
+			Country:  country,
+			Org:      mashEncoding(org),
+			Registry: registry,
+		})
+		changes.Readded = append(changes.Readded, PrefixChange{
+			Prefix:     addr,
+			Registry:   registry,
+			Org:        mashEncoding(org),
+			OldOrg:     lastEntry.Org,
+			Address:    mashEncoding(address),
+			OldAddress: lastEntry.Address,
+			Country:    country,
+		})
 		return
 	}
 
 	sNOrg := squashCosmeticChanges(org)
 	sNAdd := squashCosmeticChanges(address)
-	lastEntry := info.data[addr][len(info.data[addr])-1]
 	sOOrg := squashCosmeticChanges(lastEntry.Org)
 	sOAdd := squashCosmeticChanges(lastEntry.Address)
 
-	if sNOrg != sOOrg || sNAdd != sOAdd {
+	// Entries recorded before registries were tracked have no registry, so
+	// only a move between two known registries counts as a change.
+	movedRegistry := lastEntry.Registry != "" && lastEntry.Registry != registry
+
+	if sNOrg != sOOrg || sNAdd != sOAdd || movedRegistry {
 		info.data[addr] = append(info.data[addr], RegistrationEntry{
-			Date:    date,
-			Type:    "change",
-			Source:  source,
-			Address: mashEncoding(address),
-			Country: country,
-			Org:     mashEncoding(org),
+			Date:     date,
+			Type:     "change",
+			Source:   source,
+			Address:  mashEncoding(address),
+			Country:  country,
+			Org:      mashEncoding(org),
+			Registry: registry,
 		})
+
+		change := PrefixChange{
+			Prefix:   addr,
+			Registry: registry,
+			Org:      mashEncoding(org),
+			Address:  mashEncoding(address),
+			Country:  country,
+		}
+		switch {
+		case sNOrg != sOOrg:
+			change.OldOrg = lastEntry.Org
+			if sNAdd != sOAdd {
+				change.OldAddress = lastEntry.Address
+			}
+			changes.Renamed = append(changes.Renamed, change)
+		case sNAdd != sOAdd:
+			change.OldAddress = lastEntry.Address
+			changes.Addresses = append(changes.Addresses, change)
+		default:
+			change.OldRegistry = lastEntry.Registry
+			changes.Moved = append(changes.Moved, change)
+		}
+	}
+}
+
+// registryForEntries returns the IEEE registry of a prefix. Older entries
+// predate the registry field, so it falls back to the IEEE source file name
+// and finally to the block size.
+func registryForEntries(prefix string, entries []RegistrationEntry) string {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Registry != "" {
+			return entries[i].Registry
+		}
+		if r, ok := ieee.SourceRegistries[entries[i].Source]; ok {
+			return r
+		}
 	}
+	return ieee.PrefixRegistry(prefix)
 }
 
 func updateAge(info *MACUpdate, addr, date, source string) {
@@ -214,8 +468,28 @@ func parseDate(date string) int {
 	return int(result)
 }
 
+// checkMergeDate refuses to merge under a date before the newest recorded
+// entry. The history of each prefix is kept in date order and its last entry
+// is taken as current, so a backfill behind a later change would roll that
+// change back.
+func checkMergeDate(info *MACUpdate) error {
+	newest, newestPrefix := "", ""
+	for prefix, entries := range info.data {
+		for _, entry := range entries {
+			d := parseDate(entry.Date)
+			if d > parseDate(newest) || (d == parseDate(newest) && prefix < newestPrefix) {
+				newest, newestPrefix = entry.Date, prefix
+			}
+		}
+	}
+	if parseDate(info.today) < parseDate(newest) {
+		return fmt.Errorf("date %s is before the newest recorded entry (%s on %s)", info.today, newestPrefix, newest)
+	}
+	return nil
+}
+
 func loadCurrent(info *MACUpdate) error {
-	jsonPath := filepath.Join(info.dir, "data", "macs.json")
+	jsonPath := info.dataPath("macs.json")
 	fileData, err := os.ReadFile(jsonPath)
 	if err != nil {
 		return err
@@ -224,7 +498,7 @@ func loadCurrent(info *MACUpdate) error {
 }
 
 func loadCurrentMACAges(info *MACUpdate) error {
-	csvPath := filepath.Join(info.dir, "data", "mac-ages.csv")
+	csvPath := info.dataPath("mac-ages.csv")
 	csvFile, err := os.Open(csvPath)
 	if err != nil {
 		return err
@@ -248,33 +522,17 @@ func loadCurrentMACAges(info *MACUpdate) error {
 
 var matchRegistry = regexp.MustCompile(`^Registry$`)
 
-func loadIEEEURLs(info *MACUpdate) error {
-	ieeeURLs := []struct {
-		url        string
-		minRecords int
-	}{
-		{"https://standards-oui.ieee.org/oui/oui.csv", 38831},
-		{"https://standards-oui.ieee.org/cid/cid.csv", 210},
-		{"https://standards-oui.ieee.org/iab/iab.csv", 4575},
-		{"https://standards-oui.ieee.org/oui28/mam.csv", 6235},
-		{"https://standards-oui.ieee.org/oui36/oui36.csv", 6873},
-	}
-
-	for _, urlInfo := range ieeeURLs {
+// loadIEEE merges every IEEE registry file from src into the dataset.
+func loadIEEE(info *MACUpdate, src Source) error {
+	seen := make(map[string]bool)
+	for _, file := range info.registryFiles() {
 		processed := make(map[string]bool)
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
-		records, err := downloadIEEECSV(info, ctx, urlInfo.url)
-		cancel()
-
+		records, err := fetchIEEE(info, src, file)
 		if err != nil {
 			return err
 		}
 
-		if len(records) < urlInfo.minRecords {
-			return fmt.Errorf("URL %s only has %d records (wanted >= %d)", urlInfo.url, len(records), urlInfo.minRecords)
-		}
-
 		for _, rec := range records {
 			if len(rec) < 4 {
 				continue
@@ -296,7 +554,7 @@ func loadIEEEURLs(info *MACUpdate) error {
 
 			// Skip duplicates
 			if processed[addr] {
-				log.Printf("Skipping duplicate registration for %s from %s [%+v] addr=%s", addr, urlInfo.url, rec, addr)
+				log.Printf("Skipping duplicate registration for %s from %s [%+v] addr=%s", addr, file.name, rec, addr)
 				continue
 			}
 
@@ -306,86 +564,68 @@ func loadIEEEURLs(info *MACUpdate) error {
 			// Remove any \r characters
 			address = strings.ReplaceAll(address, "\r", "")
 
-			sourceName := "ieee-" + filepath.Base(urlInfo.url)
-			updateRegistration(info, addr, info.today, rec[2], address, sourceName)
+			sourceName := "ieee-" + file.name
+			updateRegistration(info, addr, info.today, rec[2], address, sourceName, rec[0])
 			updateAge(info, addr, info.today, sourceName)
 			processed[addr] = true
+			seen[addr] = true
 		}
 	}
 
+	findRemoved(info, seen)
 	return nil
 }
 
-func downloadIEEECSV(info *MACUpdate, ctx context.Context, url string) ([][]string, error) {
-	name := path.Base(url)
-	fpath := path.Join(info.dir, "data", "ieee", name)
-
-	var rdata []byte
-	var err error
-
-	// Retry logic: up to 26 attempts (initial + 25 retries)
-	for retries := 0; retries <= MaxRetries; retries++ {
-		if retries > 0 {
-			time.Sleep(5 * time.Second)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			continue
-		}
-
-		req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36")
+// fetchIEEE fetches and parses a registry file, rejecting truncated files.
+func fetchIEEE(info *MACUpdate, src Source, file ieeeFile) ([][]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
 
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			// Don't retry on timeout or context cancellation
-			if ctx.Err() != nil {
-				return nil, ctx.Err()
-			}
-			if retries == MaxRetries {
-				return nil, err
-			}
-			log.Printf("HTTP error %s from %s, retrying...", err, url)
-			time.Sleep(time.Second)
-			continue
-		}
+	return fetchIEEECSV(info, ctx, src, file)
+}
 
-		rdata, err = io.ReadAll(resp.Body)
-		resp.Body.Close()
+// fetchIEEECSV fetches a registry file from src and parses it. Only a file
+// that parses and has at least the expected number of records is stored in
+// data/ieee and committed to the source.
+func fetchIEEECSV(info *MACUpdate, ctx context.Context, src Source, file ieeeFile) ([][]string, error) {
+	name := file.name
+	fpath := info.dataPath("ieee", name)
 
-		if err != nil {
-			if retries == MaxRetries {
-				return nil, err
-			}
-			log.Printf("HTTP read error %s %s, retrying...", err, url)
-			time.Sleep(time.Second)
-			continue
-		}
+	rdata, err := src.Fetch(ctx, name)
+	if err != nil {
+		return nil, err
+	}
 
-		if resp.StatusCode != 200 {
-			if retries == MaxRetries {
-				return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
-			}
-			log.Printf("HTTP %d from %s, retrying...", resp.StatusCode, url)
-			time.Sleep(time.Second)
-			continue
-		}
-		// Success
-		break
+	current, err := os.ReadFile(fpath)
+	if err == nil && len(rdata) < len(current)-512 {
+		return nil, fmt.Errorf("Downloaded file %s from %s is substantially smaller than existing file %s: cur:%d, existing:%d", name, src, fpath, len(rdata), len(current))
 	}
 
-	st, err := os.Stat(fpath)
-	if err == nil && int64(len(rdata)) < (st.Size()-512) {
-		return nil, fmt.Errorf("Downloaded file %s is substantially smaller than existing file for %s: cur:%d, existing:%d", url, fpath, len(rdata), st.Size())
+	records, err := parseIEEECSV(rdata)
+	if err != nil {
+		return nil, fmt.Errorf("%s from %s: %w", name, src, err)
+	}
+	if len(records) < file.minRecords {
+		return nil, fmt.Errorf("%s from %s only has %d records (wanted >= %d)", name, src, len(records), file.minRecords)
 	}
 
 	// Write the registry files exactly as provided from IEEE (weird line endings/quotes/etcs)
-	if err := os.WriteFile(fpath, rdata, 0644); err != nil {
-		return nil, err
+	if !bytes.Equal(rdata, current) && !info.dryRun {
+		if err := os.WriteFile(fpath, rdata, 0644); err != nil {
+			return nil, err
+		}
+	}
+	if c, ok := src.(committer); ok {
+		if err := c.Commit(name); err != nil {
+			return nil, err
+		}
 	}
 
-	// Parse CSV
-	// Remove trailing newlines before parsing
+	return records, nil
+}
+
+// parseIEEECSV parses a registry file, trimming whitespace from every field.
+func parseIEEECSV(rdata []byte) ([][]string, error) {
 	reader := csv.NewReader(bytes.NewReader(rdata))
 	reader.LazyQuotes = true // liberal_parsing equivalent
 	reader.TrimLeadingSpace = true
@@ -427,13 +667,13 @@ func writeResults(info *MACUpdate) error {
 		return err
 	}
 
-	jsonPath := filepath.Join(info.dir, "data", "macs.json")
+	jsonPath := info.dataPath("macs.json")
 	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
 		return err
 	}
 
 	// Write MAC ages CSV
-	csvPath := filepath.Join(info.dir, "data", "mac-ages.csv")
+	csvPath := info.dataPath("mac-ages.csv")
 	csvFile, err := os.Create(csvPath)
 	if err != nil {
 		return err
@@ -464,14 +704,8 @@ func writeResults(info *MACUpdate) error {
 	}
 
 	// Write updated timestamp
-	updatedPath := filepath.Join(info.dir, "data", "updated.txt")
-	if err := os.WriteFile(updatedPath, []byte(info.now), 0644); err != nil {
-		return err
-	}
-
-	// Write updated binary OUI table
-	writeOUIBin(info)
-	return nil
+	updatedPath := info.dataPath("updated.txt")
+	return os.WriteFile(updatedPath, []byte(info.now), 0644)
 }
 
 func getBaseDirectory() string {
@@ -526,6 +760,7 @@ func writeOUIBin(info *MACUpdate) {
 		lastOrg := ""
 		lastCountry := ""
 		lastAddress := ""
+		removed := ""
 		for _, entry := range entries {
 			if len(firstAdded) == 0 && entry.Type == "add" {
 				firstAdded = strings.TrimSpace(entry.Date)
@@ -533,28 +768,59 @@ func writeOUIBin(info *MACUpdate) {
 			lastOrg = strings.TrimSpace(entry.Org)
 			lastAddress = strings.TrimSpace(entry.Address)
 			lastCountry = strings.TrimSpace(entry.Country)
+			// A removal stays in effect until the prefix is re-added
+			removed = ""
+			if entry.Type == "remove" {
+				removed = strings.TrimSpace(entry.Date)
+			}
 		}
 
 		var oui [6]byte
 		copy(oui[:], addr)
 		mkey := hex.EncodeToString(oui[:]) + "/" + strconv.Itoa(maskInt)
 
+		vendor := sanitizeString(lastOrg)
+		address := sanitizeString(lastAddress)
+		parts := ieee.ParseAddress(address)
+		country := parts.Country
+		if country == "" {
+			country = sanitizeString(strings.ToUpper(lastCountry))
+		}
 		db.Blocks[mkey] = &mactracker.OuiBlock{
-			Oui:     oui[:],
-			Mask:    maskInt,
-			Vendor:  sanitizeString(lastOrg),
-			Added:   firstAdded,
-			Country: sanitizeString(strings.ToUpper(lastCountry)),
-			Address: sanitizeString(lastAddress),
+			Oui:         oui[:],
+			Mask:        maskInt,
+			Vendor:      vendor,
+			VendorShort: mactracker.NormalizeVendor(vendor),
+			OrgID:       mactracker.VendorOrgID(vendor),
+			Added:       firstAdded,
+			Country:     country,
+			Address:     address,
+			Street:      parts.Street,
+			City:        parts.City,
+			Region:      parts.Region,
+			PostalCode:  parts.PostalCode,
+			Removed:     removed,
+			Registry:    registryForEntries(prefix, entries),
 		}
 	}
 
+	db.Info.Built = time.Now().UTC()
+	sources, err := ieeeSourceChecksums(info.dataPath("ieee"))
+	if err != nil {
+		log.Fatalf("error checksumming IEEE sources: %s", err)
+	}
+	db.Info.Sources = sources
+
 	data, err := mactracker.EncodeOUIDB(db)
 	if err != nil {
 		log.Fatalf("error encoding OUI database: %s", err)
 	}
 
 	outPath := filepath.Join(info.dir, "oui_table.bin.gz")
+	if info.dryRun {
+		log.Printf("Dry run: not writing %d entries to %s (%d bytes)", len(db.Blocks), outPath, len(data))
+		return
+	}
 	if err := os.WriteFile(outPath, data, 0644); err != nil {
 		log.Fatalf("error writing OUI database: %s", err)
 	}
@@ -563,6 +829,24 @@ func writeOUIBin(info *MACUpdate) {
 	log.Printf("[**] MAC OUI information update complete")
 }
 
+// ieeeSourceChecksums returns the SHA-256 of each IEEE CSV in dir the table is built from.
+func ieeeSourceChecksums(dir string) ([]mactracker.OuiSource, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	sources := make([]mactracker.OuiSource, 0, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, mactracker.OuiSource{Name: filepath.Base(p), SHA256: sha256.Sum256(data)})
+	}
+	return sources, nil
+}
+
 // sanitizeString scrubs a given string of invalid UTF8 and nulls
 func sanitizeString(s string) string {
 	s = strings.ToValidUTF8(s, "")
@@ -1,7 +1,13 @@
 package main
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	mactracker "github.com/runZeroInc/mac-tracker"
 )
 
 func TestMashEncoding(t *testing.T) {
@@ -61,3 +67,218 @@ func TestSortablePrefix(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateRegistrationRegistry(t *testing.T) {
+	info := &MACUpdate{ages: make(MACAges), data: make(MACData)}
+
+	updateRegistration(info, "ea2701000000/24", "2026-01-01", "ACCE Technology Corp.", "Hsinchu City  TW 300024", "ieee-cid.csv", "CID")
+	if got := info.data["ea2701000000/24"]; len(got) != 1 || got[0].Registry != "CID" {
+		t.Fatalf("add entry = %+v, want registry CID", got)
+	}
+
+	// Unchanged registrations don't add entries
+	updateRegistration(info, "ea2701000000/24", "2026-01-02", "ACCE Technology Corp.", "Hsinchu City  TW 300024", "ieee-cid.csv", "CID")
+	if got := info.data["ea2701000000/24"]; len(got) != 1 {
+		t.Fatalf("got %d entries after unchanged update, want 1", len(got))
+	}
+
+	// Moving between registries is a change
+	updateRegistration(info, "ea2701000000/24", "2026-01-03", "ACCE Technology Corp.", "Hsinchu City  TW 300024", "ieee-oui.csv", "MA-L")
+	if got := info.data["ea2701000000/24"]; len(got) != 2 || got[1].Type != "change" || got[1].Registry != "MA-L" {
+		t.Fatalf("entries after registry move = %+v", got)
+	}
+
+	// Entries recorded before registries were tracked don't get a change
+	// record when their registry is first seen; the table falls back to
+	// registryForEntries instead
+	info.data["286fb9000000/24"] = []RegistrationEntry{{Date: "2015-01-01", Type: "add", Org: "Nokia Shanghai Bell Co., Ltd.", Address: "Shanghai CN"}}
+	updateRegistration(info, "286fb9000000/24", "2026-01-01", "Nokia Shanghai Bell Co., Ltd.", "Shanghai CN", "ieee-oui.csv", "MA-L")
+	if got := info.data["286fb9000000/24"]; len(got) != 1 || got[0].Registry != "" {
+		t.Fatalf("entries after first registry sighting = %+v, want the original entry", got)
+	}
+}
+
+func TestCheckMergeDate(t *testing.T) {
+	info := &MACUpdate{
+		today: "2026-01-15",
+		data: MACData{
+			"001bc5000000/24": {
+				{Date: "2008-01-01", Type: "add", Source: "ieee-oui.csv", Org: "Old Name"},
+				{Date: "2026-02-01", Type: "change", Source: "ieee-oui.csv", Org: "New Name"},
+			},
+		},
+	}
+	// Backfilling behind the later change would make Old Name current again
+	err := checkMergeDate(info)
+	if err == nil || !strings.Contains(err.Error(), "001bc5000000/24 on 2026-02-01") {
+		t.Errorf("checkMergeDate before the newest entry = %v, want an error naming it", err)
+	}
+	for _, today := range []string{"2026-02-01", "2026-02-02"} {
+		info.today = today
+		if err := checkMergeDate(info); err != nil {
+			t.Errorf("checkMergeDate on %s = %v", today, err)
+		}
+	}
+}
+
+func TestRegistryForEntries(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		entries  []RegistrationEntry
+		expected string
+	}{
+		{"ea2701000000/24", []RegistrationEntry{{Source: "ieee-oui.csv"}, {Source: "ieee-cid.csv", Registry: "CID"}}, "CID"},
+		{"c85ce2700000/28", []RegistrationEntry{{Source: "wireshark.org"}, {Source: "ieee-mam.csv"}}, "MA-M"},
+		{"0050c2f71000/36", []RegistrationEntry{{Source: "wireshark.org"}}, "IAB"},
+		{"70b3d5c3c000/36", []RegistrationEntry{{Source: "deepmac"}}, "MA-S"},
+		{"000e02000000/24", []RegistrationEntry{{Source: "wireshark.org"}}, "MA-L"},
+		// A /24 with the local bit set may be a CID, so the size says nothing
+		{"0a1b2c000000/24", []RegistrationEntry{{Source: "wireshark.org"}}, ""},
+		{"02608c000000/24", []RegistrationEntry{{Source: "wireshark.org"}}, ""},
+	}
+
+	for _, test := range tests {
+		result := registryForEntries(test.prefix, test.entries)
+		if result != test.expected {
+			t.Errorf("registryForEntries(%q) = %q, want %q", test.prefix, result, test.expected)
+		}
+	}
+}
+
+func TestSelectRegistryFiles(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+		err   bool
+	}{
+		{"", []string{"oui.csv", "cid.csv", "iab.csv", "mam.csv", "oui36.csv"}, false},
+		{"mam", []string{"mam.csv"}, false},
+		{"oui36.csv, MA-L", []string{"oui36.csv", "oui.csv"}, false},
+		{"ma-s,oui36", []string{"oui36.csv"}, false},
+		{"cid,bogus", nil, true},
+	}
+	for _, test := range tests {
+		files, err := selectRegistryFiles(test.input)
+		if (err != nil) != test.err {
+			t.Errorf("selectRegistryFiles(%q) error = %v", test.input, err)
+			continue
+		}
+		var got []string
+		for _, f := range files {
+			got = append(got, f.name)
+		}
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("selectRegistryFiles(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestRunMergeStages(t *testing.T) {
+	cid, err := os.ReadFile(filepath.Join("..", "..", "data", "ieee", "cid.csv"))
+	if err != nil {
+		t.Skip("no IEEE registry files in data/ieee")
+	}
+	dataDir := filepath.Join(t.TempDir(), "data")
+	if err := os.MkdirAll(filepath.Join(dataDir, "ieee"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "ieee", "cid.csv"), cid, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "macs.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := selectRegistryFiles("cid")
+	newInfo := func(dryRun bool) *MACUpdate {
+		return &MACUpdate{
+			ages:    make(MACAges),
+			data:    make(MACData),
+			today:   "2026-01-26",
+			dir:     filepath.Dir(dataDir),
+			dataDir: dataDir,
+			files:   files,
+			dryRun:  dryRun,
+		}
+	}
+	src := &DirSource{Dir: filepath.Join(dataDir, "ieee")}
+
+	// A dry run reports the changes without writing them
+	info := newInfo(true)
+	if err := runMerge(info, src); err != nil {
+		t.Fatalf("dry run merge: %v", err)
+	}
+	if changes := info.changelog(); len(changes.Added) < 200 || len(changes.Renamed)+len(changes.Addresses) != 0 {
+		t.Errorf("dry run merged %s", changes.Summary())
+	}
+	if data, _ := os.ReadFile(filepath.Join(dataDir, "macs.json")); string(data) != "{}" {
+		t.Errorf("dry run wrote macs.json")
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "mac-ages.csv")); err == nil {
+		t.Errorf("dry run wrote mac-ages.csv")
+	}
+
+	info = newInfo(false)
+	if err := runMerge(info, src); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if err := runBuild(newInfo(false)); err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	db, err := mactracker.LoadOUIDBFile(filepath.Join(filepath.Dir(dataDir), "oui_table.bin.gz"))
+	if err != nil {
+		t.Fatalf("load built table: %v", err)
+	}
+	if len(db.Blocks) != len(info.changelog().Added) {
+		t.Errorf("built table has %d blocks, want %d", len(db.Blocks), len(info.changelog().Added))
+	}
+	for _, block := range db.Blocks {
+		if block.Added != "2026-01-26" || block.Registry != mactracker.RegistryCID {
+			t.Errorf("%s added %s in %s, want 2026-01-26 in CID", block.Key(), block.Added, block.Registry)
+			break
+		}
+	}
+
+	// Backfilling behind the merge just made is refused before anything is written
+	merged, _ := os.ReadFile(filepath.Join(dataDir, "macs.json"))
+	info = newInfo(false)
+	info.today = "2026-01-20"
+	if err := runMerge(info, src); err == nil {
+		t.Error("merge dated before the newest entry succeeded")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dataDir, "macs.json")); !bytes.Equal(data, merged) {
+		t.Error("refused backfill rewrote macs.json")
+	}
+}
+
+func TestWriteOUIBinRemoved(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "data", "ieee"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	info := &MACUpdate{
+		dir: dir,
+		data: MACData{
+			"70b3d5c3d000/36": {
+				{Date: "2016-02-01", Type: "add", Source: "ieee-oui36.csv", Org: "Gone GmbH", Registry: "MA-S"},
+				{Date: "2018-07-01", Type: "remove", Source: "ieee-oui36.csv", Org: "Gone GmbH", Registry: "MA-S"},
+			},
+			"8c1f64aaa000/36": {
+				{Date: "2021-01-01", Type: "add", Source: "ieee-oui36.csv", Org: "Lapsed", Registry: "MA-S"},
+				{Date: "2023-01-01", Type: "remove", Source: "ieee-oui36.csv", Org: "Lapsed", Registry: "MA-S"},
+				{Date: "2024-01-01", Type: "re-add", Source: "ieee-oui36.csv", Org: "Renewed", Registry: "MA-S"},
+			},
+		},
+	}
+	writeOUIBin(info)
+
+	db, err := mactracker.LoadOUIDBFile(filepath.Join(dir, "oui_table.bin.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := db.Blocks["70b3d5c3d000/36"]; b == nil || b.Active() || b.Removed != "2018-07-01" {
+		t.Errorf("removed block = %+v", b)
+	}
+	if b := db.Blocks["8c1f64aaa000/36"]; b == nil || !b.Active() || b.Vendor != "Renewed" || b.Added != "2021-01-01" {
+		t.Errorf("re-added block = %+v", b)
+	}
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source fetches the IEEE registry CSV files.
+type Source interface {
+	// Fetch returns the contents of a registry file, such as "oui.csv".
+	Fetch(ctx context.Context, name string) ([]byte, error)
+	// String describes the source in log messages.
+	String() string
+}
+
+// A committer is a Source that keeps state about the files it fetches, which
+// it updates only once the caller has validated a file and calls Commit.
+type committer interface {
+	Commit(name string) error
+}
+
+// ieeeFile is one of the IEEE registry files an update reads.
+type ieeeFile struct {
+	name       string // File name, as stored in data/ieee
+	path       string // Path relative to the IEEE base URL
+	minRecords int    // Fewer records than this means a truncated download
+}
+
+// ieeeFiles lists the registry files in the order they are merged.
+var ieeeFiles = []ieeeFile{
+	{"oui.csv", "oui/oui.csv", 38831},
+	{"cid.csv", "cid/cid.csv", 210},
+	{"iab.csv", "iab/iab.csv", 4575},
+	{"mam.csv", "oui28/mam.csv", 6235},
+	{"oui36.csv", "oui36/oui36.csv", 6873},
+}
+
+// IEEEBaseURL is where the IEEE publishes the registry files.
+const IEEEBaseURL = "https://standards-oui.ieee.org/"
+
+// HTTPSource downloads registry files from the IEEE, or from a mirror or test
+// server with the same layout.
+//
+// With a CacheDir, the source keeps the ETag and Last-Modified of each file
+// in a sidecar file there and sends conditional requests, returning the
+// cached copy when the server reports it unchanged. A fetch is only recorded
+// in the sidecar once the caller accepts it with Commit.
+type HTTPSource struct {
+	BaseURL    string        // Defaults to IEEEBaseURL
+	Client     *http.Client  // Defaults to http.DefaultClient
+	UserAgent  string        // Defaults to UserAgent
+	CacheDir   string        // Directory holding the last fetched copies, such as data/ieee
+	Retries    int           // Attempts after the first
+	Backoff    time.Duration // Delay before the first retry, doubled for each one after
+	MaxBackoff time.Duration // Longest delay between attempts, and longest Retry-After honored
+
+	// wait pauses between attempts; tests replace it to record the delays.
+	wait func(ctx context.Context, d time.Duration) error
+
+	// pending holds the records of fetches not committed yet, by file name.
+	pending fetchState
+}
+
+// UserAgent identifies the updater to the IEEE.
+const UserAgent = "mac-tracker-update (+https://github.com/runZeroInc/mac-tracker)"
+
+// NewHTTPSource returns a source for the registry files under baseURL with
+// the default retry policy, caching fetched files in cacheDir.
+func NewHTTPSource(baseURL, cacheDir string) *HTTPSource {
+	return &HTTPSource{
+		BaseURL:    baseURL,
+		CacheDir:   cacheDir,
+		Retries:    MaxRetries,
+		Backoff:    2 * time.Second,
+		MaxBackoff: 2 * time.Minute,
+	}
+}
+
+func (s *HTTPSource) String() string {
+	return s.baseURL()
+}
+
+func (s *HTTPSource) baseURL() string {
+	if s.BaseURL == "" {
+		return IEEEBaseURL
+	}
+	return strings.TrimSuffix(s.BaseURL, "/") + "/"
+}
+
+// URL returns the address of the named registry file.
+func (s *HTTPSource) URL(name string) string {
+	for _, f := range ieeeFiles {
+		if f.name == name {
+			return s.baseURL() + f.path
+		}
+	}
+	return s.baseURL() + name
+}
+
+// Fetch downloads the named registry file, retrying failed requests with
+// exponential backoff. When the cached copy is current it is returned
+// without downloading the file again. The fetch is not recorded in the
+// sidecar file until Commit is called.
+func (s *HTTPSource) Fetch(ctx context.Context, name string) ([]byte, error) {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	url := s.URL(name)
+
+	var state fetchState
+	var cached []byte
+	if s.CacheDir != "" {
+		var err error
+		if state, err = loadFetchState(filepath.Join(s.CacheDir, fetchStateFile)); err != nil {
+			return nil, err
+		}
+		cached = state.cached(s.CacheDir, name, url)
+	}
+	rec := state[name]
+	if rec == nil || cached == nil {
+		rec = &fetchRecord{}
+	}
+
+	var res *fetchResult
+	var err error
+	for attempt := 0; attempt <= s.Retries; attempt++ {
+		if attempt > 0 {
+			delay := s.backoff(attempt, res)
+			log.Printf("%v, retrying in %s...", err, delay.Round(time.Millisecond))
+			if err := s.pause(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err = s.get(ctx, client, url, rec)
+		if err == nil || !res.retry {
+			break
+		}
+		// Don't retry on timeout or context cancellation
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := res.data
+	if res.status == http.StatusNotModified {
+		if cached == nil {
+			return nil, fmt.Errorf("HTTP 304 from %s without a conditional request", url)
+		}
+		log.Printf("%s is unchanged since %s", url, rec.Changed.Format(time.RFC3339))
+		data = cached
+	}
+	if state != nil {
+		state.record(name, url, res, data)
+		if s.pending == nil {
+			s.pending = make(fetchState)
+		}
+		s.pending[name] = state[name]
+	}
+	return data, nil
+}
+
+// Commit records the last fetch of the named file in the sidecar file, once
+// the caller has validated it and stored it in the cache directory. Files
+// that are never committed are downloaded again on the next run.
+func (s *HTTPSource) Commit(name string) error {
+	rec := s.pending[name]
+	if rec == nil {
+		return nil
+	}
+	path := filepath.Join(s.CacheDir, fetchStateFile)
+	state, err := loadFetchState(path)
+	if err != nil {
+		return err
+	}
+	state[name] = rec
+	if err := state.save(path); err != nil {
+		return err
+	}
+	delete(s.pending, name)
+	return nil
+}
+
+// fetchResult is the outcome of a single request.
+type fetchResult struct {
+	status       int
+	data         []byte
+	etag         string
+	lastModified string
+	retryAfter   time.Duration // Delay asked for by the server, if any
+	retry        bool          // Whether the request may succeed if repeated
+}
+
+// get makes a single request for url, conditional on rec's validators.
+func (s *HTTPSource) get(ctx context.Context, client *http.Client, url string, rec *fetchRecord) (*fetchResult, error) {
+	res := &fetchResult{}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return res, err
+	}
+	ua := s.UserAgent
+	if ua == "" {
+		ua = UserAgent
+	}
+	req.Header.Set("User-Agent", ua)
+	if rec.ETag != "" {
+		req.Header.Set("If-None-Match", rec.ETag)
+	}
+	if rec.LastModified != "" {
+		req.Header.Set("If-Modified-Since", rec.LastModified)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		res.retry = true
+		return res, fmt.Errorf("HTTP error %w from %s", err, url)
+	}
+	defer resp.Body.Close()
+
+	res.status = resp.StatusCode
+	res.etag = resp.Header.Get("ETag")
+	res.lastModified = resp.Header.Get("Last-Modified")
+	res.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotModified:
+		return res, nil
+	default:
+		// Rate limiting and server errors are usually temporary
+		res.retry = resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode >= 500
+		return res, fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
+	}
+
+	res.data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		res.retry = true
+		return res, fmt.Errorf("HTTP read error %w from %s", err, url)
+	}
+	return res, nil
+}
+
+// backoff returns the delay before the given retry: an exponentially growing,
+// jittered delay, or longer if the last response asked for it.
+func (s *HTTPSource) backoff(attempt int, last *fetchResult) time.Duration {
+	limit := s.MaxBackoff
+	if limit <= 0 {
+		limit = time.Minute
+	}
+	delay := s.Backoff
+	for i := 1; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+	delay = min(delay, limit)
+	// Equal jitter keeps at least half the delay
+	if delay > 1 {
+		delay = delay/2 + rand.N(delay/2)
+	}
+	if last != nil && last.retryAfter > delay {
+		delay = min(last.retryAfter, limit)
+	}
+	return delay
+}
+
+func (s *HTTPSource) pause(ctx context.Context, d time.Duration) error {
+	if s.wait != nil {
+		return s.wait(ctx, d)
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseRetryAfter returns the delay in a Retry-After header, given as
+// seconds or an HTTP date, or zero when there is none.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if when, err := http.ParseTime(v); err == nil {
+		return max(when.Sub(now), 0)
+	}
+	return 0
+}
+
+// DirSource reads registry files from a local directory, such as data/ieee
+// or an offline mirror.
+type DirSource struct {
+	Dir string
+}
+
+func (s *DirSource) String() string {
+	return s.Dir
+}
+
+// Fetch reads the named registry file from the directory.
+func (s *DirSource) Fetch(ctx context.Context, name string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(s.Dir, name))
+}
+
+// parseSource returns the source named by the -source flag: "ieee" for the
+// IEEE website, an http or https base URL for a mirror, or a directory.
+// Downloads are cached in cacheDir.
+func parseSource(v, cacheDir string) (Source, error) {
+	switch {
+	case v == "" || v == "ieee":
+		return NewHTTPSource(IEEEBaseURL, cacheDir), nil
+	case strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://"):
+		return NewHTTPSource(v, cacheDir), nil
+	}
+	st, err := os.Stat(v)
+	if err != nil {
+		return nil, err
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("source %s is not a directory", v)
+	}
+	return &DirSource{Dir: v}, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ieeeServer serves the registry files in dir at their IEEE paths.
+func ieeeServer(t *testing.T, dir string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	for _, f := range ieeeFiles {
+		mux.HandleFunc("/"+f.path, func(w http.ResponseWriter, r *http.Request) {
+			http.ServeFile(w, r, filepath.Join(dir, f.name))
+		})
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHTTPSourceRetries(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.URL.Path != "/oui28/mam.csv" {
+			http.NotFound(w, r)
+			return
+		}
+		if attempts < 3 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("Registry,Assignment,Organization Name,Organization Address\n"))
+	}))
+	defer srv.Close()
+
+	src := &HTTPSource{BaseURL: srv.URL, Client: srv.Client(), Retries: 2}
+	data, err := src.Fetch(context.Background(), "mam.csv")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if attempts != 3 || !bytes.HasPrefix(data, []byte("Registry,")) {
+		t.Errorf("Fetch took %d attempts and returned %q", attempts, data)
+	}
+
+	// Client errors are not retried
+	attempts = 0
+	if _, err := src.Fetch(context.Background(), "missing.csv"); err == nil {
+		t.Error("Fetch of a missing file succeeded")
+	}
+	if attempts != 1 {
+		t.Errorf("Fetch of a missing file took %d attempts, want 1", attempts)
+	}
+}
+
+func TestHTTPSourceURL(t *testing.T) {
+	tests := []struct {
+		base, name, want string
+	}{
+		{"", "oui.csv", "https://standards-oui.ieee.org/oui/oui.csv"},
+		{"http://mirror.example/ieee", "mam.csv", "http://mirror.example/ieee/oui28/mam.csv"},
+		{"http://mirror.example/ieee/", "oui36.csv", "http://mirror.example/ieee/oui36/oui36.csv"},
+	}
+	for _, test := range tests {
+		src := &HTTPSource{BaseURL: test.base}
+		if got := src.URL(test.name); got != test.want {
+			t.Errorf("URL(%q) with base %q = %q, want %q", test.name, test.base, got, test.want)
+		}
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	dir := t.TempDir()
+	if src, err := parseSource(dir, ""); err != nil || src.String() != dir {
+		t.Errorf("parseSource(%q) = %v, %v", dir, src, err)
+	}
+	if src, err := parseSource("ieee", ""); err != nil || src.String() != IEEEBaseURL {
+		t.Errorf(`parseSource("ieee", "") = %v, %v`, src, err)
+	}
+	if src, err := parseSource("http://127.0.0.1:8080", ""); err != nil || src.String() != "http://127.0.0.1:8080/" {
+		t.Errorf("parseSource(URL) = %v, %v", src, err)
+	}
+	if _, err := parseSource(filepath.Join(dir, "missing"), ""); err == nil {
+		t.Error("parseSource accepted a missing directory")
+	}
+}
+
+// TestLoadIEEESources loads the checked-in registry files through an HTTP
+// test server and from a directory, and checks both give the same dataset.
+func TestLoadIEEESources(t *testing.T) {
+	ieeeDir := filepath.Join("..", "..", "data", "ieee")
+	if _, err := os.Stat(filepath.Join(ieeeDir, "oui.csv")); err != nil {
+		t.Skip("no IEEE registry files in data/ieee")
+	}
+	srv := ieeeServer(t, ieeeDir)
+
+	load := func(src Source) *MACUpdate {
+		t.Helper()
+		info := &MACUpdate{
+			ages:  make(MACAges),
+			data:  make(MACData),
+			today: "2026-01-26",
+			dir:   t.TempDir(),
+		}
+		if err := os.MkdirAll(filepath.Join(info.dir, "data", "ieee"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := loadIEEE(info, src); err != nil {
+			t.Fatalf("loadIEEE(%s): %v", src, err)
+		}
+		return info
+	}
+	viaHTTP := load(&HTTPSource{BaseURL: srv.URL, Client: srv.Client()})
+	viaDir := load(&DirSource{Dir: ieeeDir})
+
+	if len(viaHTTP.data) < 50000 || len(viaHTTP.data) != len(viaDir.data) {
+		t.Fatalf("loaded %d prefixes over HTTP and %d from a directory", len(viaHTTP.data), len(viaDir.data))
+	}
+	entries := viaHTTP.data["70b3d5c3c000/36"]
+	if len(entries) != 1 || entries[0].Source != "ieee-oui36.csv" || entries[0].Registry != "MA-S" {
+		t.Errorf("70b3d5c3c000/36 = %+v", entries)
+	}
+
+	// The fetched files are stored as received
+	for _, f := range ieeeFiles {
+		want, _ := os.ReadFile(filepath.Join(ieeeDir, f.name))
+		got, err := os.ReadFile(filepath.Join(viaHTTP.dir, "data", "ieee", f.name))
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("stored %s differs from the source (%v)", f.name, err)
+		}
+	}
+}
@@ -16,7 +16,9 @@
 // # Looking up raw bytes
 //
 // When you already have the address as a byte slice (e.g. from a packet
-// capture), use [LookupBytes] to avoid an unnecessary string round-trip:
+// capture), use [LookupBytes] to avoid an unnecessary string round-trip.
+// Lookups use a longest-prefix-match index built when a table is first used
+// and do not allocate:
 //
 //	addr := []byte{0x00, 0x50, 0x56, 0x12, 0x34, 0x56}
 //	block := mactracker.LookupBytes(addr)
@@ -24,6 +26,17 @@
 //		fmt.Println(block.Vendor) // "VMware"
 //	}
 //
+// # IPv6 interface identifiers
+//
+// SLAAC addresses built from a modified EUI-64 interface identifier embed the
+// interface's MAC. [LookupIPv6] recovers it and resolves the vendor, returning
+// [ErrNotEUI64] for temporary (privacy extension) and other random identifiers:
+//
+//	block, mac, err := mactracker.LookupIPv6("fe80::250:56ff:fe12:3456")
+//	if err == nil && block != nil {
+//		fmt.Println(mac, block.Vendor) // "00:50:56:12:34:56 VMware, Inc."
+//	}
+//
 // # Parsing a MAC address
 //
 // [ParseMAC] converts a string in any common format to an [OuiHardwareAddr]:
@@ -34,6 +47,58 @@
 //	}
 //	fmt.Println(addr) // 00:50:56:12:34:56
 //
+// [ParseMAC] is deliberately lenient and strips separators wherever they
+// appear. To validate user input, [ParseMACStrict] requires one consistent
+// notation and a 6, 8 or 20-byte length, and reports what was wrong:
+//
+//	_, err := mactracker.ParseMACStrict("00:11-22:33:44:55")
+//	var se *mactracker.MACSyntaxError
+//	if errors.As(err, &se) {
+//		fmt.Println(se.Err, se.Offset) // mixed separators 5
+//	}
+//
+// # Fixed-size addresses
+//
+// [MAC48] and [EUI64] hold an address by value. They are comparable, so they
+// work as map keys, and [ParseMAC48] and [ParseEUI64] parse without
+// allocating:
+//
+//	seen := make(map[mactracker.MAC48]time.Time)
+//	m, err := mactracker.ParseMAC48("00:50:56:12:34:56")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	seen[m] = time.Now()
+//	block := mactracker.LookupBytes(m[:])
+//
+// Both implement encoding.TextMarshaler and encoding.TextUnmarshaler, and
+// convert to and from [OuiHardwareAddr] and net.HardwareAddr with Addr,
+// HardwareAddr, [MAC48FromSlice] and [EUI64FromSlice].
+//
+// # Transforming addresses
+//
+// [OuiHardwareAddr] transforms return a new address and never modify the
+// receiver: WithLAA, WithoutLAA, WithGroup and WithoutGroup flip the flag
+// bits, ToEUI64 and FromEUI64 insert or remove ff:fe, BitReversed converts
+// to and from Token Ring/FDDI bit order, and Increment and Decrement step
+// through a block without leaving it:
+//
+//	addr, _ := mactracker.ParseMAC("00:1b:c5:00:02:ff")
+//	next, ok := addr.Increment(24)
+//	fmt.Println(next, ok) // 00:1b:c5:00:03:00 true
+//
+// # Bit-reversed addresses
+//
+// Token Ring, FDDI and some vendor CLIs print addresses with the bits of each
+// byte reversed. [Resolver.LookupBitOrder] tries the canonical form and, in
+// [BitOrderAuto] mode, the bit-reversed form when nothing matches, reporting
+// which interpretation was used:
+//
+//	block, addr, order := mactracker.DefaultResolver().LookupBitOrder("10:00:04:48:2c:6a", mactracker.BitOrderAuto)
+//	fmt.Println(addr, order, block.Vendor) // 08:00:20:12:34:56 reversed Oracle Corporation
+//
+// [Resolver.SetBitOrder] applies a bit order to a resolver's Lookup and LookupBytes.
+//
 // # Detecting virtual-machine MACs
 //
 // [Lookup] returns a [OUiBlock] with a [Virtual] flag for known virtual-machine prefixes,and you can also check against the virtual table directly with [LookupVirtual],
@@ -44,6 +109,43 @@
 //	platform := mactracker.LookupVirtual("00:50:56:12:34:56")
 //	fmt.Println(platform) // "VMware"
 //
+// # Randomized addresses
+//
+// Phones and laptops rotate locally administered (LAA) addresses per network.
+// [OuiHardwareAddr.IsRandomized] flags these from the address bits alone, and
+// [Resolver.IsRandomized] also rules out addresses under an IEEE Company ID.
+// To stop a resolver attributing vendors to LAA addresses other than through
+// a CID registration, enable strict mode:
+//
+//	r := mactracker.NewResolver(&mactracker.OUITable)
+//	r.SetStrictLAA(true)
+//
+// CIDs are recognized by their [OuiBlock.Registry]. Encoded tables built
+// without registries, such as format version 1, can't tell them apart, so
+// strict mode leaves lookups in those tables unchanged.
+//
+// # Scoring suspicious addresses
+//
+// [Lookup] returning nil doesn't say why. [Assess] separates unparseable
+// input, unregistered and skip-listed prefixes, group-bit source addresses,
+// all-zero or all-ones device bits, known placeholders, and prefixes
+// registered after the device was first seen, and sums them into a score:
+//
+//	res := mactracker.Assess("02:00:00:00:00:00", firstSeen)
+//	for _, r := range res.Reasons {
+//		fmt.Println(r.Code, r.Detail) // "placeholder ...", "zero-nic ..."
+//	}
+//
+// # Multicast and reserved addresses
+//
+// Group addresses such as 01:00:5e:xx:xx:xx (IPv4 multicast), 33:33:xx:xx:xx:xx
+// (IPv6 multicast), 01:80:c2:00:00:0e (LLDP) and the VRRP and HSRP virtual
+// router ranges resolve to a block naming the protocol and the standard that
+// defines it, from [OUITableMulticast]:
+//
+//	block := mactracker.Lookup("01:80:c2:00:00:0e")
+//	fmt.Println(block.Vendor, block.Protocol, block.Standard) // "Nearest Bridge LLDP IEEE 802.1AB"
+//
 // # Looking up against individual tables
 //
 // [LookupOUI] searches only the primary IEEE registration table:
@@ -53,6 +155,8 @@
 //		fmt.Println(block.Vendor) // "Converging Systems Inc."
 //	}
 //
+// [LookupMulticast] searches only the group and reserved address table.
+//
 // [LookupOverride] searches only the override table for unofficial
 // and private registrations:
 //
@@ -61,6 +165,138 @@
 //		fmt.Println(block.Vendor) // "Govee"
 //	}
 //
+// # Custom lookup stacks
+//
+// The package-level lookups use [DefaultResolver]. A [Resolver] holds its own
+// ordered set of tables, skip list and mask widths, so differently configured
+// stacks (or test fixtures) can live side by side:
+//
+//	lab := mactracker.NewOuiDB(map[string]*mactracker.OuiBlock{
+//		"001bc5000000/24": {Oui: []byte{0x00, 0x1b, 0xc5, 0, 0, 0}, Mask: 24, Vendor: "Lab Gear"},
+//	})
+//	r := mactracker.NewResolver(lab, &mactracker.OUITable)
+//	_ = r.SetSkipPrefixes("000000000000/24")
+//	block := r.Lookup("00:1b:c5:00:02:03") // "Lab Gear"
+//
+// The default skip list is read from [OUISkipPrefixes] on first use; change
+// it afterwards with [SetOUISkipPrefixes].
+//
+// # Override files
+//
+// Private registrations (lab gear, internal appliances, unregistered IoT
+// vendors) can be kept in a CSV or JSON override file and layered above the
+// IEEE table with [LoadOverridesFile] and [Resolver.PrependTable]. Invalid
+// records are reported as an [*OverrideError] naming the offending line:
+//
+//	prefix,vendor,added,private
+//	d0:c9:07,Govee,2023-12-14,true
+//	70:b3:d5:c3:c/36,Lab Sensor,,true
+//
+//	overrides, err := mactracker.LoadOverridesFile("overrides.csv")
+//	if err != nil {
+//		log.Fatal(err) // overrides.csv:3: prefix "70:b3:d5:c3:c/36" ...
+//	}
+//	mactracker.DefaultResolver().PrependTable(overrides)
+//
+// # Updating the IEEE table at runtime
+//
+// The embedded table is fixed at build time. Long-running services can load a
+// newer table written by [EncodeOUIDB] (such as a fresh oui_table.bin.gz) and
+// swap it in while lookups are in flight with [ReloadOUITable], or with
+// [LoadOUIDBFile] and [SetOUITable] for more control:
+//
+//	if err := mactracker.ReloadOUITable("/var/lib/macs/oui_table.bin.gz"); err != nil {
+//		log.Printf("keeping current OUI table: %v", err)
+//	}
+//
+// [OuiDB.BuildInfo] reports when a table was built and from which sources.
+// The embedded table is loaded on first use, so call BuildInfo rather than
+// reading the Info field:
+//
+//	info := mactracker.ActiveOUITable().BuildInfo()
+//	fmt.Println(info.Built) // zero for tables built before version 2
+//
+// # Historical lookups
+//
+// The embedded table only holds the current owner of each prefix. To find out
+// who owned a prefix on a given date, load the full registration history from
+// data/macs.json with [LoadHistoryFile] and call [OuiHistory.LookupAt]:
+//
+//	history, err := mactracker.LoadHistoryFile("data/macs.json")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	when := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+//	if reg := history.LookupAt("70:b3:d5:c3:c0:01", when); reg != nil {
+//		fmt.Println(reg.Prefix, reg.Org) // "70b3d5c3c000/36 PEEK TRAFFIC"
+//	}
+//
+// [OuiHistory.Entries] lists every add, change, remove and re-add record for
+// a prefix. A prefix is not in effect between its removal from the IEEE
+// registry and a re-add, so LookupAt falls back to the enclosing prefix.
+//
+// # Removed registrations
+//
+// Prefixes the IEEE no longer lists stay in the table, since devices made
+// under them remain in use, with Removed set to the date they disappeared.
+// [OuiBlock.Active] reports whether a block is still registered, and
+// [Resolver.SetActiveOnly] makes a resolver skip removed blocks:
+//
+//	if block := mactracker.Lookup(mac); block != nil && !block.Active() {
+//		fmt.Println(block.Vendor, "removed on", block.Removed)
+//	}
+//
+// Removal dates are only recorded in tables of format version 2. The
+// embedded table is version 1 until the next scheduled update rebuilds it, so
+// until then every block in it is active and SetActiveOnly skips nothing.
+//
+// # Block ranges
+//
+// [OuiBlock] reports its range with First, Last, Size and Contains, and
+// Subprefixes iterates over the narrower blocks that make it up.
+// [OuiDB.Overlapping] lists the registrations that contain or sit inside a
+// prefix, such as every MA-M and MA-S carved out of a vendor's MA-L:
+//
+//	for _, b := range mactracker.OUITable.Overlapping([]byte{0x70, 0xb3, 0xd5}, 24) {
+//		fmt.Println(b.Key(), b.First(), b.Last(), b.Vendor)
+//	}
+//
+// # Searching by vendor
+//
+// [OuiDB.SearchVendor] goes the other way, from a vendor name to its blocks,
+// matching the name exactly ([VendorExact]), as a case-insensitive substring
+// ([VendorSubstring]) or ignoring punctuation and company suffixes
+// ([VendorNormalized]):
+//
+//	for _, b := range mactracker.OUITable.SearchVendor("espressif", mactracker.VendorNormalized) {
+//		fmt.Println(b.Key())
+//	}
+//
+// The lookup command does the same across every table with
+// "lookup search [-exact | -normalized] <vendor>".
+//
+// # Vendor names
+//
+// The same company is often registered under many spellings. Each block's
+// VendorShort holds a short canonical name and OrgID a stable slug for
+// grouping, computed by [NormalizeVendor] and [VendorOrgID] from the
+// registered name and the [VendorAliases] table:
+//
+//	fmt.Println(mactracker.NormalizeVendor("HUAWEI TECHNOLOGIES CO.,LTD")) // Huawei
+//	fmt.Println(mactracker.VendorOrgID("TP-LINK TECHNOLOGIES CO.,LTD."))   // tp-link
+//
+// [VendorNormalized] searches match on the organization ID, computing it
+// from the registered name for tables built without one.
+//
+// Tables built by cmd/update or cmd/gen-oui-db also split each organization
+// address into Street, City, Region, PostalCode and an ISO 3166-1 alpha-2
+// Country, which is empty when the registration names none. These fields, like
+// VendorShort, OrgID, Registry and Removed, stay empty in a table of format
+// version 1 (see [OuiDB.BuildInfo]) until it is rebuilt:
+//
+//	block := mactracker.Lookup("00:1b:c5:00:02:03")
+//	fmt.Println(block.City, block.Region, block.Country)
+//
 // # Building a CIDR-style mask
 //
 // [MaskFromCIDR] creates a byte-level mask useful for custom prefix matching:
@@ -0,0 +1,146 @@
+// Package ieee holds the parts of the IEEE registry data shared by the table
+// generators in cmd.
+package ieee
+
+import (
+	"strings"
+)
+
+// OrgAddress is an IEEE organization address split into its parts.
+type OrgAddress struct {
+	Street     string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string // ISO 3166-1 alpha-2 code, empty when none was found
+}
+
+// cityPrefixes are words that start multi-word city names, such as
+// "San Jose" or "New Taipei", so they are kept with the word after them.
+var cityPrefixes = map[string]struct{}{
+	"Ann": {}, "Baton": {}, "Cedar": {}, "Des": {}, "El": {}, "Fort": {},
+	"Ft.": {}, "Grand": {}, "Hong": {}, "Kuala": {}, "La": {}, "Las": {},
+	"Le": {}, "Los": {}, "Mountain": {}, "New": {}, "Palo": {}, "Rio": {},
+	"Saint": {}, "Salt": {}, "San": {}, "Santa": {}, "Sao": {}, "São": {},
+	"St": {}, "St.": {}, "Tel": {},
+}
+
+// placeSuffixes are words that end multi-word city and region names, such as
+// "Haidian District" or "Zhejiang Province", so they are kept with the word
+// before them.
+var placeSuffixes = map[string]struct{}{
+	"City": {}, "County": {}, "District": {}, "Prefecture": {},
+	"Pradesh": {}, "Province": {}, "Region": {}, "State": {},
+}
+
+// compassPlaces are places whose names start with a compass point, such as
+// "West Hollywood". Street names often end with one too ("Sunset Blvd
+// West"), so only these names, in lower case, keep it.
+var compassPlaces = map[string]struct{}{
+	"east brunswick": {}, "east dundee": {}, "east farmingdale": {}, "east greenbush": {},
+	"east greenwich": {}, "east hartford": {}, "east kilbride": {}, "east lansing": {},
+	"east palo alto": {}, "east peoria": {}, "east pittsburgh": {}, "east providence": {},
+	"east rochester": {}, "east rockaway": {}, "east syracuse": {}, "east tamaki": {},
+	"north andover": {}, "north attleboro": {}, "north bethesda": {}, "north billerica": {},
+	"north canton": {}, "north chelmsford": {}, "north kansas city": {}, "north kingstown": {},
+	"north las vegas": {}, "north logan": {}, "north mankato": {}, "north miami": {},
+	"north ogden": {}, "north plainfield": {}, "north point": {}, "north reading": {},
+	"north ryde": {}, "north sydney": {}, "north syracuse": {}, "north vancouver": {},
+	"north wales": {}, "north york": {},
+	"south bend": {}, "south burlington": {}, "south croydon": {}, "south elgin": {},
+	"south hackensack": {}, "south jordan": {}, "south melbourne": {}, "south pasadena": {},
+	"south plainfield": {}, "south portland": {}, "south san francisco": {}, "south windsor": {},
+	"south yarra": {},
+	"west bengal": {}, "west chester": {}, "west drayton": {}, "west hartford": {},
+	"west henrietta": {}, "west hollywood": {}, "west jordan": {}, "west lafayette": {},
+	"west molesey": {}, "west vancouver": {}, "west warwick": {}, "west windsor": {},
+	"west yorkshire": {},
+}
+
+// placeStart returns the index of the first word of the place name ending at
+// tokens[end], joining a leading city prefix, a trailing place suffix or a
+// compass point that belongs to the name. The first token is never joined,
+// as it belongs to the street.
+func placeStart(tokens []string, end int) int {
+	if end <= 1 {
+		return end
+	}
+	start := end
+	if _, ok := placeSuffixes[tokens[end]]; ok {
+		start = end - 1
+	} else if _, ok := cityPrefixes[tokens[end-1]]; ok {
+		start = end - 1
+	}
+	if start > 1 {
+		if _, ok := compassPlaces[strings.ToLower(joinWords(tokens[start-1:end+1]))]; ok {
+			start--
+		}
+	}
+	return start
+}
+
+// ParseAddress splits an IEEE organization address. The registry joins the
+// street, city, region, country and postal code with single spaces, leaving
+// a double space where a part is empty:
+//
+//	1 Infinite Loop Cupertino CA US 95014
+//	No.1, Creation Road 3, Hsinchu Science Park, Hsinchu  TW 30077
+//
+// The country is the rightmost valid ISO 3166-1 code followed by at most
+// four postal code words. A US state written after the country
+// ("San Jose CA US CA 95131") is taken as the region. Parts that cannot be
+// found are left empty, with the unparsed text kept in Street.
+func ParseAddress(address string) OrgAddress {
+	var res OrgAddress
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return res
+	}
+	tokens := strings.Split(address, " ")
+
+	ci := -1
+	for i := len(tokens) - 1; i >= 0 && i >= len(tokens)-5; i-- {
+		if _, ok := Countries[tokens[i]]; ok {
+			ci = i
+			break
+		}
+	}
+	if ci < 0 {
+		res.Street = joinWords(tokens)
+		return res
+	}
+	res.Country = tokens[ci]
+	res.PostalCode = joinWords(tokens[ci+1:])
+
+	end := ci // Tokens before end hold the street, city and region
+	if _, state := usStates[tokens[ci]]; state && ci > 0 && tokens[ci-1] == "US" {
+		res.Country = "US"
+		res.Region = tokens[ci]
+		end = ci - 1
+		if end > 1 && tokens[end-1] == res.Region {
+			end-- // The state is usually also written before the country
+		}
+	} else if end > 1 {
+		// The region is usually one word, or empty when followed by a double space
+		start := placeStart(tokens, end-1)
+		res.Region = joinWords(tokens[start:end])
+		end = start
+	}
+
+	// The city is the nearest place name before the region
+	for end > 0 && tokens[end-1] == "" {
+		end--
+	}
+	if end > 1 {
+		start := placeStart(tokens, end-1)
+		res.City = strings.TrimRight(joinWords(tokens[start:end]), ",")
+		end = start
+	}
+	res.Street = strings.TrimRight(joinWords(tokens[:end]), ",")
+	return res
+}
+
+// joinWords joins the non-empty tokens with single spaces.
+func joinWords(tokens []string) string {
+	return strings.Join(strings.Fields(strings.Join(tokens, " ")), " ")
+}
@@ -0,0 +1,134 @@
+package ieee
+
+import (
+	"encoding/csv"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	// Addresses taken from data/ieee/*.csv
+	tests := []struct {
+		input string
+		want  OrgAddress
+	}{
+		{
+			input: "1 Infinite Loop Cupertino CA US 95014 ",
+			want:  OrgAddress{Street: "1 Infinite Loop", City: "Cupertino", Region: "CA", PostalCode: "95014", Country: "US"},
+		},
+		{
+			input: "80 West Tasman Drive San Jose CA US 94568 ",
+			want:  OrgAddress{Street: "80 West Tasman Drive", City: "San Jose", Region: "CA", PostalCode: "94568", Country: "US"},
+		},
+		{
+			input: "No.1, Creation Road 3, Hsinchu Science Park, Hsinchu  TW 30077 ",
+			want:  OrgAddress{Street: "No.1, Creation Road 3, Hsinchu Science Park", City: "Hsinchu", PostalCode: "30077", Country: "TW"},
+		},
+		{
+			input: "657 Orly Ave. Dorval Quebec CA H9P 1G1",
+			want:  OrgAddress{Street: "657 Orly Ave.", City: "Dorval", Region: "Quebec", PostalCode: "H9P 1G1", Country: "CA"},
+		},
+		{
+			input: "The Charter Building, Charter Place Uxbridge  GB UB8 1JG  ",
+			want:  OrgAddress{Street: "The Charter Building, Charter Place", City: "Uxbridge", PostalCode: "UB8 1JG", Country: "GB"},
+		},
+		{
+			input: "Testvägen 16 Arlöv  SE 232 37 ",
+			want:  OrgAddress{Street: "Testvägen 16", City: "Arlöv", PostalCode: "232 37", Country: "SE"},
+		},
+		{
+			input: "E-2, Sector 63 Noida Uttar Pradesh IN 201301 ",
+			want:  OrgAddress{Street: "E-2, Sector 63", City: "Noida", Region: "Uttar Pradesh", PostalCode: "201301", Country: "IN"},
+		},
+		{
+			input: "Xiaomi Building, No.68 Qinghe Middle Street Haidian District Beijing CN 100085 ",
+			want:  OrgAddress{Street: "Xiaomi Building, No.68 Qinghe Middle Street", City: "Haidian District", Region: "Beijing", PostalCode: "100085", Country: "CN"},
+		},
+		{
+			input: "Al. Caiapos, 596 Barueri São Paulo BR 06460-110 ",
+			want:  OrgAddress{Street: "Al. Caiapos, 596", City: "Barueri", Region: "São Paulo", PostalCode: "06460-110", Country: "BR"},
+		},
+		// The state follows the country, where it used to be read as Canada
+		{
+			input: "1251 McKay Dr. San Jose CA US CA 95131 ",
+			want:  OrgAddress{Street: "1251 McKay Dr.", City: "San Jose", Region: "CA", PostalCode: "95131", Country: "US"},
+		},
+		{
+			input: "9220 Sunset Blvd #112 West Hollywood  US CA 90069 ",
+			want:  OrgAddress{Street: "9220 Sunset Blvd #112", City: "West Hollywood", Region: "CA", PostalCode: "90069", Country: "US"},
+		},
+		// Compass points only join known place names
+		{
+			input: "1 Lonsdale Ave North Vancouver BC CA V7M 2E4",
+			want:  OrgAddress{Street: "1 Lonsdale Ave", City: "North Vancouver", Region: "BC", PostalCode: "V7M 2E4", Country: "CA"},
+		},
+		{
+			input: "25 King St West Toronto Ontario CA M5L 1G3",
+			want:  OrgAddress{Street: "25 King St West", City: "Toronto", Region: "Ontario", PostalCode: "M5L 1G3", Country: "CA"},
+		},
+		{
+			input: "400 Oyster Point Blvd South San Francisco CA US 94080",
+			want:  OrgAddress{Street: "400 Oyster Point Blvd", City: "South San Francisco", Region: "CA", PostalCode: "94080", Country: "US"},
+		},
+		// No country code at all
+		{
+			input: "3F, NO.2, ChaXiSanWei Industrial Park, BaoAn District ShenZhen GuangDong  518100 ",
+			want:  OrgAddress{Street: "3F, NO.2, ChaXiSanWei Industrial Park, BaoAn District ShenZhen GuangDong 518100"},
+		},
+		{input: "", want: OrgAddress{}},
+	}
+	for _, test := range tests {
+		if got := ParseAddress(test.input); got != test.want {
+			t.Errorf("ParseAddress(%q) =\n\t%+v, want\n\t%+v", test.input, got, test.want)
+		}
+	}
+}
+
+// TestParseAddressCorpus parses every address in the IEEE registry files.
+func TestParseAddressCorpus(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "data", "ieee", "*.csv"))
+	if err != nil || len(files) == 0 {
+		t.Skip("no IEEE registry files in data/ieee")
+	}
+
+	total, found := 0, 0
+	for _, path := range files {
+		f, err := os.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader := csv.NewReader(f)
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+
+		for _, rec := range records[1:] {
+			if len(rec) < 4 || rec[3] == "" {
+				continue
+			}
+			total++
+			got := ParseAddress(rec[3])
+			if got.Country == "" {
+				continue
+			}
+			found++
+			if _, ok := Countries[got.Country]; !ok {
+				t.Errorf("%s: ParseAddress(%q) country %q is not an ISO 3166-1 code", path, rec[3], got.Country)
+			}
+		}
+	}
+
+	// A handful of registrations have no country code at all
+	if total == 0 || found*1000 < total*995 {
+		t.Errorf("found a country in %d of %d addresses, want at least 99.5%%", found, total)
+	}
+}
@@ -0,0 +1,41 @@
+package ieee
+
+import (
+	"strconv"
+	"strings"
+
+	mactracker "github.com/runZeroInc/mac-tracker"
+)
+
+// SourceRegistries maps IEEE source names to the registry each file lists.
+var SourceRegistries = map[string]string{
+	"ieee-oui.csv":   mactracker.RegistryMAL,
+	"ieee-mam.csv":   mactracker.RegistryMAM,
+	"ieee-oui36.csv": mactracker.RegistryMAS,
+	"ieee-iab.csv":   mactracker.RegistryIAB,
+	"ieee-cid.csv":   mactracker.RegistryCID,
+}
+
+// PrefixRegistry guesses the registry of a prefix, such as "0050c2123000/36",
+// from its block size, for records that predate the registry field and name
+// no IEEE source file. A /24 with the locally-administered bit set may be a
+// CID or one of the few early MA-L assignments with that bit, so it has no
+// guess.
+func PrefixRegistry(prefix string) string {
+	switch {
+	case strings.HasSuffix(prefix, "/24"):
+		if b, err := strconv.ParseUint(prefix[:min(2, len(prefix))], 16, 8); err != nil || b&2 != 0 {
+			return ""
+		}
+		return mactracker.RegistryMAL
+	case strings.HasSuffix(prefix, "/28"):
+		return mactracker.RegistryMAM
+	case strings.HasSuffix(prefix, "/36"):
+		// IABs were only ever assigned from these two IEEE-owned blocks
+		if strings.HasPrefix(prefix, "0050c2") || strings.HasPrefix(prefix, "40d855") {
+			return mactracker.RegistryIAB
+		}
+		return mactracker.RegistryMAS
+	}
+	return ""
+}
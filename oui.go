@@ -2,23 +2,52 @@ package mactracker
 
 import (
 	"encoding/hex"
+	"fmt"
 	"net"
-	"strconv"
 	"sync"
+	"sync/atomic"
 )
 
-// ouiTables is the list of sources to use for lookups, in order of priority.
-var ouiTables = []*OuiDB{
-	// Specific overrides for unofficial and private registrations
-	&OUITableExtra,
-	// Virtual machine prefixes (some of which conflict with official registrations)
-	&OUITableVirtual,
-	// The official IEEE OUI registrations
-	&OUITable,
+// ouiDirect is the configuration used by OuiDB.Lookup: the package skip list
+// and every mask width. It is compiled from OUISkipPrefixes on first use and
+// replaced by SetOUISkipPrefixes.
+var (
+	ouiDirect     atomic.Pointer[resolverState]
+	ouiDirectOnce sync.Once
+)
+
+func ouiDirectState() *resolverState {
+	ouiDirectOnce.Do(func() {
+		ouiDirect.Store(&resolverState{skip: compilePrefixKeys(OUISkipPrefixes)})
+	})
+	return ouiDirect.Load()
+}
+
+// ouiSkip returns the compiled package skip list.
+func ouiSkip() map[ouiPrefix]struct{} {
+	return ouiDirectState().skip
 }
 
-// ouiMasks defines the CIDR mask sizes we use for lookups, in order of specificity. The /16 mask size isn't official but is used by QEMU.
-var ouiMasks = []int{36, 28, 24, 16}
+// SetOUISkipPrefixes replaces the package skip list used by OuiDB.Lookup,
+// OuiHistory lookups and the DefaultResolver, taking effect for lookups
+// already in flight. Each key is a masked prefix such as "000000000000/24".
+// Resolvers from NewResolver keep their own skip lists.
+func SetOUISkipPrefixes(keys ...string) error {
+	skip := make(map[ouiPrefix]struct{}, len(keys))
+	for _, k := range keys {
+		p, ok := parsePrefixKey(k)
+		if !ok {
+			return fmt.Errorf("invalid skip prefix %q", k)
+		}
+		skip[p] = struct{}{}
+	}
+	ouiDirectState()
+	ouiDirect.Store(&resolverState{skip: skip})
+	return DefaultResolver().update(func(st *resolverState) error {
+		st.skip = skip
+		return nil
+	})
+}
 
 // OuiHardwareAddr is a 6-byte (or 8-byte) hardware address derived from net.HardwareAddr.
 type OuiHardwareAddr net.HardwareAddr
@@ -28,12 +57,43 @@ func (a OuiHardwareAddr) HasLAA() bool {
 	return a[0]&2 == 2
 }
 
-// WithoutLAA returns a copy of the address with the locally-administered bit cleared.
-func (a OuiHardwareAddr) WithoutLAA() OuiHardwareAddr {
-	if a.HasLAA() {
-		a[0] &^= 2
+// IsRandomized reports whether the address looks like a randomized or
+// private address, such as the per-network MACs used by modern phones: a
+// unicast address with the locally-administered bit set. This is a heuristic
+// on the address alone; addresses under an IEEE Company ID (CID) also match,
+// see Resolver.IsRandomized to rule those out.
+func (a OuiHardwareAddr) IsRandomized() bool {
+	return a.HasLAA() && !a.IsGroup()
+}
+
+// SLAP quadrants of locally administered addresses (IEEE 802c).
+const (
+	SLAPAAI      = "AAI"      // Administratively Assigned Identifier, including random addresses
+	SLAPELI      = "ELI"      // Extended Local Identifier, under a Company ID
+	SLAPSAI      = "SAI"      // Standard Assigned Identifier
+	SLAPReserved = "Reserved" // Reserved for future use
+)
+
+// SLAPQuadrant returns the IEEE 802c Structured Local Address Plan quadrant
+// of a locally administered address, or an empty string for universal addresses.
+func (a OuiHardwareAddr) SLAPQuadrant() string {
+	if !a.HasLAA() {
+		return ""
+	}
+	switch a[0] & 0x0c {
+	case 0x00:
+		return SLAPAAI
+	case 0x08:
+		return SLAPELI
+	case 0x0c:
+		return SLAPSAI
 	}
-	return a
+	return SLAPReserved
+}
+
+// IsGroup reports whether the individual/group (multicast) bit is set.
+func (a OuiHardwareAddr) IsGroup() bool {
+	return a[0]&1 == 1
 }
 
 // String returns the colon-separated hex representation of the address.
@@ -41,24 +101,80 @@ func (a OuiHardwareAddr) String() string {
 	return net.HardwareAddr(a).String()
 }
 
+// IEEE registries a block can be assigned from.
+const (
+	RegistryMAL = "MA-L" // MAC Address Block Large, a /24 OUI
+	RegistryMAM = "MA-M" // MAC Address Block Medium, a /28
+	RegistryMAS = "MA-S" // MAC Address Block Small, a /36 (formerly OUI-36)
+	RegistryIAB = "IAB"  // Individual Address Block, a /36 from a legacy IEEE OUI
+	RegistryCID = "CID"  // Company ID, only valid for locally administered addresses
+)
+
 // OuiBlock represents a single OUI registration entry with its prefix, mask, and metadata.
+// Vendor is the organization name as registered; VendorShort and OrgID are its
+// NormalizeVendor and VendorOrgID forms, for grouping blocks by company, when
+// the table was built with them.
+// Address is the organization address as registered, and Street, City,
+// Region and PostalCode its parts when the table was built with them.
+// Registry is one of the Registry* constants, or empty for unofficial entries.
+// Removed is the date the prefix was dropped from its IEEE registry, empty
+// while the registration is active.
+// Protocol and Standard are only set for well-known group and reserved addresses.
 type OuiBlock struct {
-	Oui     []byte
-	Mask    int
-	Vendor  string
-	Added   string
-	Country string
-	Address string
-	Virtual string
-	Private bool
+	Oui         []byte
+	Mask        int
+	Vendor      string
+	VendorShort string
+	OrgID       string
+	Added       string
+	Country     string
+	Address     string
+	Street      string
+	City        string
+	Region      string
+	PostalCode  string
+	Removed     string
+	Virtual     string
+	Private     bool
+	Registry    string
+	Protocol    string
+	Standard    string
+}
+
+// IsCID reports whether the block is an IEEE Company ID. Addresses under a
+// CID are locally administered and not globally unique. Blocks from tables
+// built before registries were recorded have no Registry and are never CIDs;
+// the prefix alone can't tell, since some early MA-L assignments such as
+// 3Com's 02:60:8C also have the locally-administered bit set.
+func (b *OuiBlock) IsCID() bool {
+	return b.Registry == RegistryCID
+}
+
+// Active reports whether the block is still listed in its registry.
+func (b *OuiBlock) Active() bool {
+	return b.Removed == ""
 }
 
 // OuiDB is a collection of OUI blocks indexed by masked-prefix keys.
-// When loadFunc is set, the Blocks map is populated lazily on first Lookup.
+// When loadFunc is set, the Blocks and Info fields are populated lazily on
+// first Lookup; use BuildInfo rather than reading Info directly, as it loads
+// the database first.
+// A longest-prefix-match index is built from Blocks on first Lookup, and a
+// vendor index on first SearchVendor, so Blocks must not be modified after
+// the database has been used.
 type OuiDB struct {
 	Blocks   map[string]*OuiBlock
+	Info     OuiDBInfo
 	loadOnce sync.Once
-	loadFunc func() map[string]*OuiBlock
+	loadFunc func() (map[string]*OuiBlock, OuiDBInfo)
+	index    *ouiIndex[*OuiBlock]
+
+	// noRegistry is set for encoded tables without any registries, such as
+	// format version 1, where CIDs can't be told from other IEEE blocks.
+	noRegistry bool
+
+	vendorOnce sync.Once
+	vendors    *vendorIndex
 }
 
 // ParseMAC parses s as an IEEE 802 MAC-48, EUI-48, or EUI-64 using one of the
@@ -71,6 +187,10 @@ type OuiDB struct {
 //	0123.4567.89ab
 //	0123.4567.89ab.cdef
 //	0123 4567 89ab cdEF
+//
+// Separators are stripped wherever they appear and the remainder is decoded as
+// hex, so the length and grouping are not checked; use ParseMACStrict to
+// validate untrusted input.
 func ParseMAC(s string) (OuiHardwareAddr, error) {
 	// Remove non-hex characters from the string
 	// As primitive as this loop is, it's extremely fast
@@ -115,50 +235,27 @@ func MaskFromCIDR(ones, bits int) []byte {
 	return m
 }
 
-func lookupKeys(address OuiHardwareAddr) []string {
-	res := make([]string, 0, len(ouiMasks))
-	for _, m := range ouiMasks {
-		mask := MaskFromCIDR(m, len(address)*8)
-		key := hex.EncodeToString(address.Mask(mask)) + "/" + strconv.Itoa(m)
-		if _, skip := OUISkipPrefixes[key]; skip {
-			continue
-		}
-		res = append(res, key)
-	}
-	return res
-}
-
-// Lookup resolves a MAC address string to the best-matching OUI block.
-// It accepts any common MAC notation (colon, dash, dot, or bare hex).
+// Lookup resolves a MAC address string to the best-matching OUI block using
+// the DefaultResolver. It accepts any common MAC notation (colon, dash, dot, or bare hex).
 // Returns nil when the address is unparseable or has no matching registration.
 func Lookup(s string) *OuiBlock {
-	addr, err := ParseMAC(s)
-	if err != nil {
-		return nil
-	}
-	return LookupBytes(addr)
+	return DefaultResolver().Lookup(s)
 }
 
 // LookupBytes is like Lookup but accepts a raw byte-slice address (6 or 8 bytes).
+// It does not allocate, which makes it suitable for per-packet lookups.
 func LookupBytes(addr []byte) *OuiBlock {
-	hwa := OuiHardwareAddr(addr)
-	for _, table := range ouiTables {
-		block := table.Lookup(hwa)
-		if block != nil {
-			return block
-		}
-	}
-	return nil
+	return DefaultResolver().LookupBytes(addr)
 }
 
-// LookupOUI searches only the primary IEEE OUI registration table for a MAC address string.
+// LookupOUI searches only the active IEEE OUI registration table for a MAC address string.
 // Returns nil when the address is unparseable or has no matching IEEE registration.
 func LookupOUI(s string) *OuiBlock {
 	addr, err := ParseMAC(s)
 	if err != nil {
 		return nil
 	}
-	return OUITable.Lookup(OuiHardwareAddr(addr))
+	return ActiveOUITable().Lookup(OuiHardwareAddr(addr))
 }
 
 // LookupOverride searches only the curated override table for unofficial and
@@ -187,18 +284,66 @@ func (address OuiHardwareAddr) Mask(mask []byte) []byte {
 	return masked
 }
 
+// load populates Blocks (when lazily loaded) and builds the prefix index.
+func (m *OuiDB) load() *ouiIndex[*OuiBlock] {
+	m.loadOnce.Do(func() {
+		if m.loadFunc != nil {
+			m.Blocks, m.Info = m.loadFunc()
+		}
+		m.index = newOuiIndex(m.Blocks)
+		m.noRegistry = m.Info.Version > 0 && !hasRegistry(m.Blocks)
+	})
+	return m.index
+}
+
+// hasRegistry reports whether any of the blocks records its IEEE registry.
+func hasRegistry(blocks map[string]*OuiBlock) bool {
+	for _, b := range blocks {
+		if b.Registry != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildInfo returns how the database was built, loading it first if it is
+// loaded lazily, such as the embedded OUITable. It is safe to call
+// concurrently with lookups.
+func (m *OuiDB) BuildInfo() OuiDBInfo {
+	m.load()
+	return m.Info
+}
+
 // Lookup searches the database for the most-specific OUI block matching address.
+// Only 6-byte and 8-byte addresses are matched. Lookup does not allocate.
 func (m *OuiDB) Lookup(address OuiHardwareAddr) *OuiBlock {
-	if m.loadFunc != nil {
-		m.loadOnce.Do(func() { m.Blocks = m.loadFunc() })
+	return m.lookup(address, ouiDirectState())
+}
+
+// lookup returns the most-specific block matching address under the given
+// resolver configuration.
+func (m *OuiDB) lookup(address OuiHardwareAddr, st *resolverState) *OuiBlock {
+	bits, width, ok := addrBits(address)
+	if !ok {
+		return nil
 	}
-	for _, k := range lookupKeys(address) {
-		if _, skip := OUISkipPrefixes[k]; skip {
+	x := m.load()
+	cidOnly := st.strictLAA && address.HasLAA() && !m.noRegistry
+	for i := x.first(bits, width); i >= 0; i = x.next(i, bits, width) {
+		e := &x.entries[i]
+		if !st.masks.has(e.prefix.mask) {
+			continue
+		}
+		if _, skipped := st.skip[e.prefix]; skipped {
 			continue
 		}
-		if f, ok := m.Blocks[k]; ok {
-			return f
+		if cidOnly && !e.value.IsCID() {
+			continue
+		}
+		if st.activeOnly && !e.value.Active() {
+			continue
 		}
+		return e.value
 	}
 	return nil
 }
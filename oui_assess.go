@@ -0,0 +1,156 @@
+package mactracker
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Reasons reported by Assess, in rough order of severity.
+const (
+	ReasonUnparseable        = "unparseable"         // Input is not a MAC address
+	ReasonPlaceholder        = "placeholder"         // A well-known default, example or placeholder address
+	ReasonGroupAddress       = "group-address"       // Group (multicast) bit set on a source address
+	ReasonRegisteredAfter    = "registered-after"    // Prefix was registered after the device was first seen
+	ReasonUnregisteredOUI    = "unregistered-oui"    // Universal address under a prefix with no registration
+	ReasonSkippedPrefix      = "skipped-prefix"      // Prefix is on the resolver's skip list
+	ReasonZeroNIC            = "zero-nic"            // Device-specific bits are all zero
+	ReasonOnesNIC            = "ones-nic"            // Device-specific bits are all ones
+	ReasonUnsupportedAddress = "unsupported-address" // Address is not 6 or 8 bytes long
+)
+
+// reasonWeights is the score each reason contributes to an Assessment.
+var reasonWeights = map[string]int{
+	ReasonUnparseable:        100,
+	ReasonPlaceholder:        80,
+	ReasonGroupAddress:       60,
+	ReasonRegisteredAfter:    50,
+	ReasonUnregisteredOUI:    40,
+	ReasonSkippedPrefix:      40,
+	ReasonZeroNIC:            30,
+	ReasonOnesNIC:            30,
+	ReasonUnsupportedAddress: 100,
+}
+
+// KnownPlaceholderMACs maps well-known default, example and placeholder
+// addresses (as bare lowercase hex) to a description.
+var KnownPlaceholderMACs = map[string]string{
+	"000000000000": "all zeros",
+	"ffffffffffff": "broadcast",
+	"020000000000": "privacy placeholder reported by Android and iOS",
+	"000102030405": "sequential example address",
+	"001122334455": "sequential example address",
+	"0123456789ab": "sequential example address",
+	"123456789abc": "sequential example address",
+	"00904cc51238": "Broadcom Wi-Fi firmware default",
+}
+
+// AssessReason is a single finding contributing to an Assessment.
+type AssessReason struct {
+	Code   string // One of the Reason* constants
+	Weight int    // Contribution to the assessment score
+	Detail string // Human-readable explanation
+}
+
+// Assessment scores how implausible a MAC address is as a real device's
+// source address. A zero score means nothing suspicious was found.
+type Assessment struct {
+	Addr    OuiHardwareAddr // Parsed address, nil if unparseable
+	Block   *OuiBlock       // Best matching registration, if any
+	Score   int             // Sum of the reason weights
+	Reasons []AssessReason
+}
+
+// Has reports whether the assessment includes the given reason code.
+func (a *Assessment) Has(code string) bool {
+	for _, r := range a.Reasons {
+		if r.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Assessment) add(code, format string, args ...any) {
+	w := reasonWeights[code]
+	a.Score += w
+	a.Reasons = append(a.Reasons, AssessReason{Code: code, Weight: w, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Assess scores a MAC address string using the DefaultResolver. When
+// firstSeen is non-zero, prefixes registered after that date are flagged.
+func Assess(s string, firstSeen time.Time) *Assessment {
+	return DefaultResolver().Assess(s, firstSeen)
+}
+
+// Assess scores a MAC address string against the resolver's tables. When
+// firstSeen is non-zero, prefixes registered after that date are flagged.
+func (r *Resolver) Assess(s string, firstSeen time.Time) *Assessment {
+	addr, err := ParseMAC(s)
+	if err != nil {
+		res := &Assessment{}
+		res.add(ReasonUnparseable, "cannot parse %q: %v", s, err)
+		return res
+	}
+	return r.AssessAddr(addr, firstSeen)
+}
+
+// AssessAddr is like Assess but accepts a parsed address.
+func (r *Resolver) AssessAddr(addr OuiHardwareAddr, firstSeen time.Time) *Assessment {
+	res := &Assessment{Addr: addr}
+	bits, width, ok := addrBits(addr)
+	if !ok {
+		res.add(ReasonUnsupportedAddress, "%d-byte address", len(addr))
+		return res
+	}
+
+	if desc, found := KnownPlaceholderMACs[hex.EncodeToString(addr)]; found {
+		res.add(ReasonPlaceholder, "%s is a known placeholder (%s)", addr, desc)
+	}
+	if addr.IsGroup() {
+		res.add(ReasonGroupAddress, "group bit is set on %s", addr)
+	}
+
+	res.Block = r.LookupBytes(addr)
+	switch {
+	case res.Block != nil:
+		if !firstSeen.IsZero() && res.Block.Added != "" {
+			added, err := time.Parse("2006-01-02", res.Block.Added)
+			if err == nil && added.After(firstSeen) {
+				res.add(ReasonRegisteredAfter, "prefix registered %s, after the device was first seen on %s",
+					res.Block.Added, firstSeen.Format("2006-01-02"))
+			}
+		}
+	case r.skipped(bits, width):
+		res.add(ReasonSkippedPrefix, "prefix of %s is not a valid registration", addr)
+	case !addr.HasLAA() && !addr.IsGroup():
+		res.add(ReasonUnregisteredOUI, "universally administered address %s has no registered prefix", addr)
+	}
+
+	// The device-specific bits follow the registered block, or the OUI when
+	// there is no registration.
+	mask := 24
+	if res.Block != nil {
+		mask = res.Block.Mask
+	}
+	if mask < width {
+		nic := bits &^ prefixMask(mask) & prefixMask(width)
+		switch nic {
+		case 0:
+			res.add(ReasonZeroNIC, "device bits after /%d are all zero", mask)
+		case ^prefixMask(mask) & prefixMask(width):
+			res.add(ReasonOnesNIC, "device bits after /%d are all ones", mask)
+		}
+	}
+	return res
+}
+
+// skipped reports whether the address falls under a prefix on the skip list.
+func (r *Resolver) skipped(bits uint64, width int) bool {
+	for p := range r.state.Load().skip {
+		if int(p.mask) <= width && bits&prefixMask(int(p.mask)) == p.bits {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package mactracker
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAssess(t *testing.T) {
+	tests := []struct {
+		mac       string
+		firstSeen string
+		want      []string
+	}{
+		{mac: "00:1b:c5:00:02:03"},
+		{mac: "02:42:ac:11:00:02"},
+		{mac: "not a mac", want: []string{ReasonUnparseable}},
+		{mac: "00:1b:c5:00:02", want: []string{ReasonUnsupportedAddress}},
+		{mac: "00:00:00:00:00:00", want: []string{ReasonPlaceholder, ReasonSkippedPrefix, ReasonZeroNIC}},
+		{mac: "ff:ff:ff:ff:ff:ff", want: []string{ReasonPlaceholder, ReasonGroupAddress}},
+		{mac: "02:00:00:00:00:00", want: []string{ReasonPlaceholder, ReasonZeroNIC}},
+		{mac: "01:1b:c5:00:02:03", want: []string{ReasonGroupAddress}},
+		{mac: "00:1b:c5:00:00:00", want: []string{ReasonZeroNIC}},
+		{mac: "00:1b:c5:00:0f:ff", want: []string{ReasonOnesNIC}},
+		{mac: "00:1b:c5:00:02:03", firstSeen: "2010-01-01", want: []string{ReasonRegisteredAfter}},
+		{mac: "00:1b:c5:00:02:03", firstSeen: "2020-01-01"},
+	}
+
+	for _, test := range tests {
+		var firstSeen time.Time
+		if test.firstSeen != "" {
+			firstSeen, _ = time.Parse("2006-01-02", test.firstSeen)
+		}
+		res := Assess(test.mac, firstSeen)
+		var got []string
+		for _, r := range res.Reasons {
+			got = append(got, r.Code)
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("Assess(%q, %q) reasons = %v, want %v", test.mac, test.firstSeen, got, test.want)
+		}
+		if (res.Score == 0) != (len(test.want) == 0) {
+			t.Errorf("Assess(%q, %q) score = %d with reasons %v", test.mac, test.firstSeen, res.Score, got)
+		}
+	}
+}
+
+func TestAssessUnregistered(t *testing.T) {
+	// An empty resolver has no registrations at all
+	r := NewResolver()
+	res := r.Assess("00:1b:c5:00:02:03", time.Time{})
+	if !res.Has(ReasonUnregisteredOUI) || res.Block != nil {
+		t.Errorf("Assess with no tables = %+v, want %s", res, ReasonUnregisteredOUI)
+	}
+
+	// Locally administered addresses are not expected to be registered
+	res = r.Assess("06:1b:c5:00:02:03", time.Time{})
+	if res.Has(ReasonUnregisteredOUI) {
+		t.Errorf("Assess of LAA address = %+v, want no %s", res, ReasonUnregisteredOUI)
+	}
+}
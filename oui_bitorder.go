@@ -0,0 +1,78 @@
+package mactracker
+
+import "math/bits"
+
+// BitOrder is the bit order a resolver reads addresses in. Token Ring, FDDI
+// and some vendor CLIs print MACs with the bits of each byte reversed, which
+// otherwise resolve to an unrelated vendor.
+type BitOrder int
+
+const (
+	BitOrderCanonical BitOrder = iota // Canonical (Ethernet) order only, the default
+	BitOrderReversed                  // Bit-reversed (Token Ring/FDDI) order only
+	BitOrderAuto                      // Canonical order, then bit-reversed when nothing matches
+)
+
+// String returns "canonical", "reversed" or "auto".
+func (o BitOrder) String() string {
+	switch o {
+	case BitOrderCanonical:
+		return "canonical"
+	case BitOrderReversed:
+		return "reversed"
+	case BitOrderAuto:
+		return "auto"
+	}
+	return "unknown"
+}
+
+// SetBitOrder controls how the resolver reads the bit order of addresses
+// passed to Lookup and LookupBytes. The default is BitOrderCanonical.
+func (r *Resolver) SetBitOrder(order BitOrder) {
+	_ = r.update(func(st *resolverState) error {
+		st.bitOrder = order
+		return nil
+	})
+}
+
+// LookupBitOrder resolves a MAC address string like Lookup, and reports which
+// interpretation matched: the address in canonical order and either
+// BitOrderCanonical or BitOrderReversed. The order argument overrides the
+// resolver's own setting. The address is nil when s is unparseable and the
+// block is nil when neither interpretation matched, in which case auto mode
+// returns the address as given and BitOrderCanonical.
+func (r *Resolver) LookupBitOrder(s string, order BitOrder) (*OuiBlock, OuiHardwareAddr, BitOrder) {
+	addr, err := ParseMAC(s)
+	if err != nil {
+		return nil, nil, order
+	}
+	block, matched := r.state.Load().lookupOrder(addr, order)
+	if matched == BitOrderReversed {
+		addr = addr.BitReversed()
+	}
+	return block, addr, matched
+}
+
+// lookupOrder searches the tables for addr read in the given bit order,
+// returning the block and the order that matched, or BitOrderCanonical when
+// nothing matched in auto mode. It does not allocate.
+func (st *resolverState) lookupOrder(addr []byte, order BitOrder) (*OuiBlock, BitOrder) {
+	if order != BitOrderReversed {
+		if block := st.lookup(addr); block != nil || order == BitOrderCanonical {
+			return block, BitOrderCanonical
+		}
+	}
+	var buf [8]byte
+	if len(addr) > len(buf) {
+		return nil, order
+	}
+	rev := buf[:len(addr)]
+	for i, v := range addr {
+		rev[i] = bits.Reverse8(v)
+	}
+	block := st.lookup(rev)
+	if block == nil && order == BitOrderAuto {
+		return nil, BitOrderCanonical
+	}
+	return block, BitOrderReversed
+}
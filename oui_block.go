@@ -0,0 +1,139 @@
+package mactracker
+
+import "iter"
+
+// prefix returns the block's prefix and the width in bits of the addresses
+// it holds: 48 for 6-byte prefixes, 64 for 8-byte prefixes. Reports false
+// for malformed blocks.
+func (b *OuiBlock) prefix() (ouiPrefix, int, bool) {
+	bits, width, ok := addrBits(b.Oui)
+	if !ok || b.Mask < 0 || b.Mask > width {
+		return ouiPrefix{}, 0, false
+	}
+	return ouiPrefix{bits: bits & prefixMask(b.Mask), mask: uint8(b.Mask)}, width, true
+}
+
+// bitsToAddr converts a left-aligned address back into width/8 bytes.
+func bitsToAddr(bits uint64, width int) OuiHardwareAddr {
+	addr := make(OuiHardwareAddr, width/8)
+	for i := range addr {
+		addr[i] = byte(bits >> (56 - 8*i))
+	}
+	return addr
+}
+
+// Key returns the block's masked-prefix key, such as "70b3d5c3c000/36".
+func (b *OuiBlock) Key() string {
+	return blockKey(b)
+}
+
+// First returns the first address in the block, or nil for a malformed block.
+func (b *OuiBlock) First() OuiHardwareAddr {
+	p, width, ok := b.prefix()
+	if !ok {
+		return nil
+	}
+	return bitsToAddr(p.bits, width)
+}
+
+// Last returns the last address in the block, or nil for a malformed block.
+func (b *OuiBlock) Last() OuiHardwareAddr {
+	p, width, ok := b.prefix()
+	if !ok {
+		return nil
+	}
+	return bitsToAddr(p.last(), width)
+}
+
+// Size returns the number of addresses in the block. It returns 0 for a
+// malformed block and for a /0 EUI-64 block, whose size does not fit in a uint64.
+func (b *OuiBlock) Size() uint64 {
+	_, width, ok := b.prefix()
+	if !ok || width-b.Mask >= 64 {
+		return 0
+	}
+	return 1 << uint(width-b.Mask)
+}
+
+// Contains reports whether a 6 or 8-byte address falls within the block,
+// using the same rules as a lookup.
+func (b *OuiBlock) Contains(addr []byte) bool {
+	p, _, ok := b.prefix()
+	if !ok {
+		return false
+	}
+	bits, width, ok := addrBits(addr)
+	return ok && int(p.mask) <= width && bits&prefixMask(int(p.mask)) == p.bits
+}
+
+// Overlaps reports whether the two blocks share any address.
+func (b *OuiBlock) Overlaps(other *OuiBlock) bool {
+	p, _, ok := b.prefix()
+	q, _, ok2 := other.prefix()
+	if !ok || !ok2 {
+		return false
+	}
+	mask := int(min(p.mask, q.mask))
+	return p.bits&prefixMask(mask) == q.bits&prefixMask(mask)
+}
+
+// Subprefixes iterates over the blocks of the given mask width that make up
+// this block, in address order, such as the 4096 /36 blocks of a /24. The
+// yielded blocks only have Oui and Mask set. Nothing is yielded when the mask
+// is narrower than the block's own or wider than its addresses.
+func (b *OuiBlock) Subprefixes(mask int) iter.Seq[*OuiBlock] {
+	return func(yield func(*OuiBlock) bool) {
+		p, width, ok := b.prefix()
+		if !ok || mask < int(p.mask) || mask > width {
+			return
+		}
+		step := ^prefixMask(mask) + 1
+		last := p.last()
+		for v := p.bits; ; v += step {
+			if !yield(&OuiBlock{Oui: bitsToAddr(v, width), Mask: mask}) {
+				return
+			}
+			if v|^prefixMask(mask) == last {
+				return
+			}
+		}
+	}
+}
+
+// Overlapping returns every block in the database that overlaps the prefix,
+// both the blocks containing it and the blocks it contains, ordered by first
+// address with wider blocks first. Bits of the prefix beyond the mask are
+// ignored. Returns nil when the prefix is longer than 8 bytes or the mask is
+// out of range.
+func (m *OuiDB) Overlapping(prefix []byte, mask int) []*OuiBlock {
+	if len(prefix) > 8 || mask < 0 || mask > 64 {
+		return nil
+	}
+	var bits uint64
+	for i := range 8 {
+		bits <<= 8
+		if i < len(prefix) {
+			bits |= uint64(prefix[i])
+		}
+	}
+	return m.overlapping(ouiPrefix{bits: bits & prefixMask(mask), mask: uint8(mask)})
+}
+
+// OverlappingKey is like Overlapping but accepts a masked-prefix key, such as
+// "70b3d5000000/24". Returns nil when the key cannot be parsed.
+func (m *OuiDB) OverlappingKey(key string) []*OuiBlock {
+	p, ok := parsePrefixKey(key)
+	if !ok {
+		return nil
+	}
+	return m.overlapping(p)
+}
+
+func (m *OuiDB) overlapping(p ouiPrefix) []*OuiBlock {
+	x := m.load()
+	var res []*OuiBlock
+	for i := range x.overlapping(p) {
+		res = append(res, x.entries[i].value)
+	}
+	return res
+}
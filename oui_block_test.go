@@ -0,0 +1,150 @@
+package mactracker
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOuiBlockRange(t *testing.T) {
+	tests := []struct {
+		block OuiBlock
+		first string
+		last  string
+		size  uint64
+	}{
+		{block: OuiBlock{Oui: []byte{0x70, 0xb3, 0xd5, 0x00, 0x00, 0x00}, Mask: 24}, first: "70:b3:d5:00:00:00", last: "70:b3:d5:ff:ff:ff", size: 1 << 24},
+		{block: OuiBlock{Oui: []byte{0x70, 0xb3, 0xd5, 0xc3, 0xc0, 0x00}, Mask: 36}, first: "70:b3:d5:c3:c0:00", last: "70:b3:d5:c3:cf:ff", size: 4096},
+		{block: OuiBlock{Oui: []byte{0x01, 0x00, 0x5e, 0x00, 0x00, 0x00}, Mask: 25}, first: "01:00:5e:00:00:00", last: "01:00:5e:7f:ff:ff", size: 1 << 23},
+		{block: OuiBlock{Oui: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, Mask: 48}, first: "ff:ff:ff:ff:ff:ff", last: "ff:ff:ff:ff:ff:ff", size: 1},
+		{block: OuiBlock{Oui: []byte{0x00, 0x1b, 0xc5, 0xff, 0xfe, 0x00, 0x00, 0x00}, Mask: 40}, first: "00:1b:c5:ff:fe:00:00:00", last: "00:1b:c5:ff:fe:ff:ff:ff", size: 1 << 24},
+		// Bits beyond the mask are ignored
+		{block: OuiBlock{Oui: []byte{0x70, 0xb3, 0xd5, 0xc3, 0xc1, 0x23}, Mask: 36}, first: "70:b3:d5:c3:c0:00", last: "70:b3:d5:c3:cf:ff", size: 4096},
+	}
+	for _, test := range tests {
+		b := &test.block
+		if got := b.First().String(); got != test.first {
+			t.Errorf("%s First() = %s, want %s", b.Key(), got, test.first)
+		}
+		if got := b.Last().String(); got != test.last {
+			t.Errorf("%s Last() = %s, want %s", b.Key(), got, test.last)
+		}
+		if got := b.Size(); got != test.size {
+			t.Errorf("%s Size() = %d, want %d", b.Key(), got, test.size)
+		}
+		if !b.Contains(b.First()) || !b.Contains(b.Last()) {
+			t.Errorf("%s does not contain its own first and last addresses", b.Key())
+		}
+		if prev, ok := b.First().Decrement(0); ok && b.Contains(prev) {
+			t.Errorf("%s contains %s, before its first address", b.Key(), prev)
+		}
+		if next, ok := b.Last().Increment(0); ok && b.Contains(next) {
+			t.Errorf("%s contains %s, after its last address", b.Key(), next)
+		}
+	}
+
+	bad := &OuiBlock{Oui: []byte{0x00, 0x1b}, Mask: 16}
+	if bad.First() != nil || bad.Last() != nil || bad.Size() != 0 || bad.Contains([]byte{0, 0x1b, 0, 0, 0, 0}) {
+		t.Error("malformed block reported a range")
+	}
+	if (&OuiBlock{Oui: make([]byte, 8), Mask: 0}).Size() != 0 {
+		t.Error("Size() of a /0 EUI-64 block did not report overflow")
+	}
+}
+
+func TestOuiBlockSubprefixes(t *testing.T) {
+	b := &OuiBlock{Oui: []byte{0x70, 0xb3, 0xd5, 0x00, 0x00, 0x00}, Mask: 24}
+
+	var keys []string
+	for sub := range b.Subprefixes(36) {
+		keys = append(keys, sub.Key())
+	}
+	if len(keys) != 4096 {
+		t.Fatalf("Subprefixes(36) yielded %d blocks, want 4096", len(keys))
+	}
+	if keys[0] != "70b3d5000000/36" || keys[1] != "70b3d5001000/36" || keys[4095] != "70b3d5fff000/36" {
+		t.Errorf("Subprefixes(36) = %s, %s ... %s", keys[0], keys[1], keys[4095])
+	}
+
+	// Stopping early, the block itself, and out-of-range masks
+	n := 0
+	for range b.Subprefixes(28) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("Subprefixes(28) did not stop early")
+	}
+	if got := slices.Collect(b.Subprefixes(24)); len(got) != 1 || got[0].Key() != "70b3d5000000/24" {
+		t.Errorf("Subprefixes(24) = %v", got)
+	}
+	if got := slices.Collect(b.Subprefixes(16)); len(got) != 0 {
+		t.Errorf("Subprefixes(16) yielded %d blocks, want 0", len(got))
+	}
+	if got := slices.Collect(b.Subprefixes(49)); len(got) != 0 {
+		t.Errorf("Subprefixes(49) yielded %d blocks, want 0", len(got))
+	}
+	bcast := &OuiBlock{Oui: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xf0}, Mask: 44}
+	if got := slices.Collect(bcast.Subprefixes(48)); len(got) != 16 || got[15].Key() != "ffffffffffff/48" {
+		t.Errorf("Subprefixes(48) at the top of the address space = %d blocks", len(got))
+	}
+}
+
+func TestOuiDBOverlapping(t *testing.T) {
+	db := NewOuiDB(map[string]*OuiBlock{
+		"001bc5000000/24": {Oui: []byte{0x00, 0x1b, 0xc5, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Outer"},
+		"001bc5000000/36": {Oui: []byte{0x00, 0x1b, 0xc5, 0x00, 0x00, 0x00}, Mask: 36, Vendor: "First"},
+		"001bc5001000/36": {Oui: []byte{0x00, 0x1b, 0xc5, 0x00, 0x10, 0x00}, Mask: 36, Vendor: "Second"},
+		"001bc5800000/28": {Oui: []byte{0x00, 0x1b, 0xc5, 0x80, 0x00, 0x00}, Mask: 28, Vendor: "Medium"},
+		"001bc5800000/36": {Oui: []byte{0x00, 0x1b, 0xc5, 0x80, 0x00, 0x00}, Mask: 36, Vendor: "Nested"},
+		"001bc6000000/24": {Oui: []byte{0x00, 0x1b, 0xc6, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Neighbour"},
+		"001bc4000000/24": {Oui: []byte{0x00, 0x1b, 0xc4, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Before"},
+	})
+
+	vendors := func(blocks []*OuiBlock) []string {
+		var res []string
+		for _, b := range blocks {
+			res = append(res, b.Vendor)
+		}
+		return res
+	}
+	tests := []struct {
+		prefix []byte
+		mask   int
+		want   []string
+	}{
+		{prefix: []byte{0x00, 0x1b, 0xc5}, mask: 24, want: []string{"Outer", "First", "Second", "Medium", "Nested"}},
+		{prefix: []byte{0x00, 0x1b, 0xc5, 0x80}, mask: 28, want: []string{"Outer", "Medium", "Nested"}},
+		{prefix: []byte{0x00, 0x1b, 0xc5, 0x80, 0x00, 0x42}, mask: 48, want: []string{"Outer", "Medium", "Nested"}},
+		{prefix: []byte{0x00, 0x1b, 0xc5, 0x40}, mask: 28, want: []string{"Outer"}},
+		{prefix: []byte{0x00, 0x1b}, mask: 16, want: []string{"Before", "Outer", "First", "Second", "Medium", "Nested", "Neighbour"}},
+		{prefix: []byte{0x00, 0x1c}, mask: 16, want: nil},
+		{prefix: make([]byte, 9), mask: 24, want: nil},
+	}
+	for _, test := range tests {
+		got := vendors(db.Overlapping(test.prefix, test.mask))
+		if !slices.Equal(got, test.want) {
+			t.Errorf("Overlapping(%x/%d) = %v, want %v", test.prefix, test.mask, got, test.want)
+		}
+	}
+
+	if got := vendors(db.OverlappingKey("001bc5800000/28")); !slices.Equal(got, []string{"Outer", "Medium", "Nested"}) {
+		t.Errorf("OverlappingKey = %v", got)
+	}
+	if got := db.OverlappingKey("bogus"); got != nil {
+		t.Errorf("OverlappingKey(bogus) = %v, want nil", got)
+	}
+
+	// Every MA-S under the IEEE's 70b3d5 MA-L sits inside it
+	blocks := OUITable.Overlapping([]byte{0x70, 0xb3, 0xd5}, 24)
+	if len(blocks) < 2 || blocks[0].Mask != 24 {
+		t.Fatalf("Overlapping(70b3d5/24) returned %d blocks", len(blocks))
+	}
+	outer := blocks[0]
+	for _, b := range blocks[1:] {
+		if !outer.Contains(b.First()) || !outer.Contains(b.Last()) || !b.Overlaps(outer) {
+			t.Errorf("%s is not inside %s", b.Key(), outer.Key())
+		}
+	}
+}
@@ -7,10 +7,38 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strconv"
+	"time"
 )
 
-// Binary format for OUI database:
+// Binary format for OUI database, version 2 (written by EncodeOUIDB):
+//
+//	Header:
+//	  4 bytes  magic   "OUI\x02"
+//	  8 bytes  built   int64 LE, build time in Unix seconds (0 if unknown)
+//	  2 bytes  nSrc    uint16 LE, number of source checksums
+//	Per source:
+//	  2 bytes  nLen    uint16 LE source name length
+//	  nLen     name    UTF-8
+//	  32 bytes sha256  checksum of the source file
+//	Then:
+//	  4 bytes  count   uint32 LE, number of entries
+//	Per entry:
+//	  8 bytes  oui     raw prefix bytes, zero padded
+//	  1 byte   mask    CIDR mask width
+//	  1 byte   nField  number of fields
+//	Per field:
+//	  1 byte   tag     field tag (see ouiField*)
+//	  2 bytes  fLen    uint16 LE value length
+//	  fLen     value   UTF-8
+//
+// Readers skip fields with unknown tags, so new fields can be added without
+// changing the version. Empty fields are not written.
+//
+// Version 1 (still accepted by DecodeOUIDB) has no build time, sources or
+// tagged fields:
 //
 //	Header:
 //	  4 bytes  magic   "OUI\x01"
@@ -27,14 +55,73 @@ import (
 //	  2 bytes  dLen    uint16 LE address string length
 //	  dLen     address UTF-8
 
-var ouiMagic = [4]byte{'O', 'U', 'I', 0x01}
+var (
+	ouiMagicV1 = [4]byte{'O', 'U', 'I', 0x01}
+	ouiMagicV2 = [4]byte{'O', 'U', 'I', 0x02}
+)
+
+// Field tags used by the version 2 format.
+const (
+	ouiFieldVendor   = 1
+	ouiFieldAdded    = 2
+	ouiFieldCountry  = 3
+	ouiFieldAddress  = 4
+	ouiFieldVirtual  = 5
+	ouiFieldPrivate  = 6
+	ouiFieldRegistry = 7
+	ouiFieldProtocol = 8
+	ouiFieldStandard = 9
+	ouiFieldShort    = 10
+	ouiFieldOrgID    = 11
+	ouiFieldStreet   = 12
+	ouiFieldCity     = 13
+	ouiFieldRegion   = 14
+	ouiFieldPostal   = 15
+	ouiFieldRemoved  = 16
+)
+
+// OuiDBInfo describes how an encoded database was built.
+type OuiDBInfo struct {
+	Version int         // Format version the database was decoded from
+	Built   time.Time   // Build time, zero if unknown
+	Sources []OuiSource // Checksums of the files the database was built from
+}
+
+// OuiSource identifies a source file by name and SHA-256 checksum.
+type OuiSource struct {
+	Name   string
+	SHA256 [32]byte
+}
 
-// EncodeOUIDB serializes an OuiDB into a gzip-compressed binary blob.
+// EncodeOUIDB serializes an OuiDB, including db.Info, into a gzip-compressed
+// version 2 binary blob. Blocks are written in key order, so encoding the
+// same blocks always gives the same bytes.
 func EncodeOUIDB(db *OuiDB) ([]byte, error) {
 	var raw bytes.Buffer
 
 	// Magic
-	raw.Write(ouiMagic[:])
+	raw.Write(ouiMagicV2[:])
+
+	// Build time and sources
+	var built int64
+	if !db.Info.Built.IsZero() {
+		built = db.Info.Built.Unix()
+	}
+	if err := binary.Write(&raw, binary.LittleEndian, built); err != nil {
+		return nil, err
+	}
+	if len(db.Info.Sources) > 65535 {
+		return nil, fmt.Errorf("too many sources: %d", len(db.Info.Sources))
+	}
+	if err := binary.Write(&raw, binary.LittleEndian, uint16(len(db.Info.Sources))); err != nil {
+		return nil, err
+	}
+	for _, src := range db.Info.Sources {
+		if err := writeString16(&raw, src.Name); err != nil {
+			return nil, err
+		}
+		raw.Write(src.SHA256[:])
+	}
 
 	// Entry count
 	count := uint32(len(db.Blocks))
@@ -42,8 +129,8 @@ func EncodeOUIDB(db *OuiDB) ([]byte, error) {
 		return nil, err
 	}
 
-	for _, block := range db.Blocks {
-		if err := encodeBlock(&raw, block); err != nil {
+	for _, key := range slices.Sorted(maps.Keys(db.Blocks)) {
+		if err := encodeBlock(&raw, db.Blocks[key]); err != nil {
 			return nil, err
 		}
 	}
@@ -64,17 +151,53 @@ func EncodeOUIDB(db *OuiDB) ([]byte, error) {
 }
 
 func encodeBlock(w *bytes.Buffer, b *OuiBlock) error {
-	// OUI prefix: always 6 bytes (pad if shorter)
-	var oui [6]byte
+	// OUI prefix: always 8 bytes (pad if shorter)
+	var oui [8]byte
 	copy(oui[:], b.Oui)
 	w.Write(oui[:])
 
 	// Mask
 	w.WriteByte(byte(b.Mask))
 
-	// Strings: vendor, added, country, address
-	for _, s := range []string{b.Vendor, b.Added, b.Country, b.Address} {
-		if err := writeString16(w, s); err != nil {
+	// Tagged fields, skipping empty values
+	private := ""
+	if b.Private {
+		private = "1"
+	}
+	fields := []struct {
+		tag   byte
+		value string
+	}{
+		{ouiFieldVendor, b.Vendor},
+		{ouiFieldAdded, b.Added},
+		{ouiFieldCountry, b.Country},
+		{ouiFieldAddress, b.Address},
+		{ouiFieldVirtual, b.Virtual},
+		{ouiFieldPrivate, private},
+		{ouiFieldRegistry, b.Registry},
+		{ouiFieldProtocol, b.Protocol},
+		{ouiFieldStandard, b.Standard},
+		{ouiFieldShort, b.VendorShort},
+		{ouiFieldOrgID, b.OrgID},
+		{ouiFieldStreet, b.Street},
+		{ouiFieldCity, b.City},
+		{ouiFieldRegion, b.Region},
+		{ouiFieldPostal, b.PostalCode},
+		{ouiFieldRemoved, b.Removed},
+	}
+	n := 0
+	for _, f := range fields {
+		if f.value != "" {
+			n++
+		}
+	}
+	w.WriteByte(byte(n))
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		w.WriteByte(f.tag)
+		if err := writeString16(w, f.value); err != nil {
 			return err
 		}
 	}
@@ -93,16 +216,26 @@ func writeString16(w *bytes.Buffer, s string) error {
 }
 
 // DecodeOUIDB deserializes a gzip-compressed binary blob into an OuiDB Blocks map.
+// Both version 1 and version 2 blobs are accepted.
 func DecodeOUIDB(data []byte) (map[string]*OuiBlock, error) {
+	blocks, _, err := DecodeOUIDBInfo(data)
+	return blocks, err
+}
+
+// DecodeOUIDBInfo is like DecodeOUIDB but also returns the build information
+// stored in the blob. Version 1 blobs only report their version.
+func DecodeOUIDBInfo(data []byte) (map[string]*OuiBlock, OuiDBInfo, error) {
+	var info OuiDBInfo
+
 	gz, err := gzip.NewReader(bytes.NewReader(data))
 	if err != nil {
-		return nil, fmt.Errorf("gzip open: %w", err)
+		return nil, info, fmt.Errorf("gzip open: %w", err)
 	}
 	defer gz.Close()
 
 	raw, err := io.ReadAll(gz)
 	if err != nil {
-		return nil, fmt.Errorf("gzip read: %w", err)
+		return nil, info, fmt.Errorf("gzip read: %w", err)
 	}
 
 	r := bytes.NewReader(raw)
@@ -110,30 +243,69 @@ func DecodeOUIDB(data []byte) (map[string]*OuiBlock, error) {
 	// Magic
 	var magic [4]byte
 	if _, err := io.ReadFull(r, magic[:]); err != nil {
-		return nil, fmt.Errorf("read magic: %w", err)
+		return nil, info, fmt.Errorf("read magic: %w", err)
 	}
-	if magic != ouiMagic {
-		return nil, fmt.Errorf("bad magic: %x", magic)
+	decode := decodeBlockV2
+	switch magic {
+	case ouiMagicV1:
+		info.Version = 1
+		decode = decodeBlockV1
+	case ouiMagicV2:
+		info.Version = 2
+		if err := decodeInfoV2(r, &info); err != nil {
+			return nil, info, err
+		}
+	default:
+		return nil, info, fmt.Errorf("bad magic: %x", magic)
 	}
 
 	// Count
 	var count uint32
 	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
-		return nil, fmt.Errorf("read count: %w", err)
+		return nil, info, fmt.Errorf("read count: %w", err)
 	}
 
-	blocks := make(map[string]*OuiBlock, count)
+	// Cap the preallocation so a corrupt count can't exhaust memory
+	blocks := make(map[string]*OuiBlock, min(count, 1<<20))
 	for range count {
-		block, key, err := decodeBlock(r)
+		block, key, err := decode(r)
 		if err != nil {
-			return nil, err
+			return nil, info, err
 		}
 		blocks[key] = block
 	}
-	return blocks, nil
+	return blocks, info, nil
 }
 
-func decodeBlock(r *bytes.Reader) (*OuiBlock, string, error) {
+func decodeInfoV2(r *bytes.Reader, info *OuiDBInfo) error {
+	var built int64
+	if err := binary.Read(r, binary.LittleEndian, &built); err != nil {
+		return fmt.Errorf("read build time: %w", err)
+	}
+	if built != 0 {
+		info.Built = time.Unix(built, 0).UTC()
+	}
+
+	var nSrc uint16
+	if err := binary.Read(r, binary.LittleEndian, &nSrc); err != nil {
+		return fmt.Errorf("read source count: %w", err)
+	}
+	for range nSrc {
+		var src OuiSource
+		name, err := readString16(r)
+		if err != nil {
+			return fmt.Errorf("read source name: %w", err)
+		}
+		src.Name = name
+		if _, err := io.ReadFull(r, src.SHA256[:]); err != nil {
+			return fmt.Errorf("read source checksum: %w", err)
+		}
+		info.Sources = append(info.Sources, src)
+	}
+	return nil
+}
+
+func decodeBlockV1(r *bytes.Reader) (*OuiBlock, string, error) {
 	// OUI prefix
 	var oui [6]byte
 	if _, err := io.ReadFull(r, oui[:]); err != nil {
@@ -178,6 +350,80 @@ func decodeBlock(r *bytes.Reader) (*OuiBlock, string, error) {
 	return block, key, nil
 }
 
+func decodeBlockV2(r *bytes.Reader) (*OuiBlock, string, error) {
+	// OUI prefix
+	var oui [8]byte
+	if _, err := io.ReadFull(r, oui[:]); err != nil {
+		return nil, "", fmt.Errorf("read oui: %w", err)
+	}
+
+	// Mask
+	maskByte, err := r.ReadByte()
+	if err != nil {
+		return nil, "", fmt.Errorf("read mask: %w", err)
+	}
+	mask := int(maskByte)
+
+	// Prefixes that fit in 48 bits keep the 6-byte form used by the tables
+	prefix := oui[:6]
+	if mask > 48 {
+		prefix = oui[:]
+	}
+	block := &OuiBlock{Oui: prefix, Mask: mask}
+
+	// Tagged fields
+	nField, err := r.ReadByte()
+	if err != nil {
+		return nil, "", fmt.Errorf("read field count: %w", err)
+	}
+	for range nField {
+		tag, err := r.ReadByte()
+		if err != nil {
+			return nil, "", fmt.Errorf("read field tag: %w", err)
+		}
+		value, err := readString16(r)
+		if err != nil {
+			return nil, "", fmt.Errorf("read field %d: %w", tag, err)
+		}
+		switch tag {
+		case ouiFieldVendor:
+			block.Vendor = value
+		case ouiFieldAdded:
+			block.Added = value
+		case ouiFieldCountry:
+			block.Country = value
+		case ouiFieldAddress:
+			block.Address = value
+		case ouiFieldVirtual:
+			block.Virtual = value
+		case ouiFieldPrivate:
+			block.Private = value == "1"
+		case ouiFieldRegistry:
+			block.Registry = value
+		case ouiFieldProtocol:
+			block.Protocol = value
+		case ouiFieldStandard:
+			block.Standard = value
+		case ouiFieldShort:
+			block.VendorShort = value
+		case ouiFieldOrgID:
+			block.OrgID = value
+		case ouiFieldStreet:
+			block.Street = value
+		case ouiFieldCity:
+			block.City = value
+		case ouiFieldRegion:
+			block.Region = value
+		case ouiFieldPostal:
+			block.PostalCode = value
+		case ouiFieldRemoved:
+			block.Removed = value
+		}
+	}
+
+	return block, blockKey(block), nil
+}
+
 func readString16(r *bytes.Reader) (string, error) {
 	var length uint16
 	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
@@ -0,0 +1,161 @@
+package mactracker
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestEncodeOUIDBRoundTrip(t *testing.T) {
+	built := time.Date(2026, 1, 26, 12, 30, 0, 0, time.UTC)
+	src := &OuiDB{
+		Blocks: map[string]*OuiBlock{
+			"001c42000000/24":     {Oui: []byte{0x00, 0x1c, 0x42, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Parallels, Inc.", VendorShort: "Parallels", OrgID: "parallels-intl", Added: "2007-05-13", Virtual: VirtTypeParallels, Registry: "MA-L"},
+			"d0c907000000/24":     {Oui: []byte{0xd0, 0xc9, 0x07, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Govee", Private: true},
+			"70b3d5c3c000/36":     {Oui: []byte{0x70, 0xb3, 0xd5, 0xc3, 0xc0, 0x00}, Mask: 36, Vendor: "PEEK TRAFFIC", Country: "US", Address: "5401 N SAM HOUSTON PKWY W HOUSTON TX US 77086", Street: "5401 N SAM HOUSTON PKWY W", City: "HOUSTON", Region: "TX", PostalCode: "77086", Registry: "MA-S"},
+			"0a00000000000000/56": {Oui: []byte{0x0a, 0, 0, 0, 0, 0, 0, 0}, Mask: 56, Vendor: "Wide", Removed: "2025-06-01"},
+			"0180c200000e/48":     {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x0e}, Mask: 48, Vendor: "Nearest Bridge", Protocol: "LLDP", Standard: "IEEE 802.1AB"},
+		},
+		Info: OuiDBInfo{
+			Built:   built,
+			Sources: []OuiSource{{Name: "oui.csv", SHA256: sha256.Sum256([]byte("oui"))}},
+		},
+	}
+
+	data, err := EncodeOUIDB(src)
+	if err != nil {
+		t.Fatalf("EncodeOUIDB: %v", err)
+	}
+	blocks, info, err := DecodeOUIDBInfo(data)
+	if err != nil {
+		t.Fatalf("DecodeOUIDBInfo: %v", err)
+	}
+
+	if info.Version != 2 || !info.Built.Equal(built) {
+		t.Errorf("info = %+v, want version 2 built %s", info, built)
+	}
+	if len(info.Sources) != 1 || info.Sources[0] != src.Info.Sources[0] {
+		t.Errorf("sources = %+v, want %+v", info.Sources, src.Info.Sources)
+	}
+	if len(blocks) != len(src.Blocks) {
+		t.Fatalf("decoded %d blocks, want %d", len(blocks), len(src.Blocks))
+	}
+	for key, want := range src.Blocks {
+		got := blocks[key]
+		if got == nil {
+			t.Errorf("missing block %s", key)
+			continue
+		}
+		if !bytes.Equal(got.Oui, want.Oui) || got.Mask != want.Mask || got.Vendor != want.Vendor ||
+			got.Added != want.Added || got.Country != want.Country || got.Address != want.Address ||
+			got.Virtual != want.Virtual || got.Private != want.Private || got.Registry != want.Registry ||
+			got.Protocol != want.Protocol || got.Standard != want.Standard || got.Street != want.Street ||
+			got.City != want.City || got.Region != want.Region || got.PostalCode != want.PostalCode || got.Removed != want.Removed {
+			t.Errorf("block %s = %+v, want %+v", key, got, want)
+		}
+		if got.VendorShort != want.VendorShort || got.OrgID != want.OrgID {
+			t.Errorf("block %s vendor = %q/%q, want %q/%q", key, got.VendorShort, got.OrgID, want.VendorShort, want.OrgID)
+		}
+	}
+
+	// Normalized names are left to the generator rather than derived while
+	// decoding, which would slow down the first lookup
+	if got := blocks["70b3d5c3c000/36"]; got.VendorShort != "" || got.OrgID != "" {
+		t.Errorf("decoded vendor = %q/%q, want none", got.VendorShort, got.OrgID)
+	}
+}
+
+func TestEncodeOUIDBDeterministic(t *testing.T) {
+	db := &OuiDB{Blocks: make(map[string]*OuiBlock)}
+	for i := range 64 {
+		oui := []byte{0x00, 0x1b, byte(i), 0, 0, 0}
+		db.Blocks[blockKey(&OuiBlock{Oui: oui, Mask: 24})] = &OuiBlock{Oui: oui, Mask: 24, Vendor: "Vendor"}
+	}
+	first, err := EncodeOUIDB(db)
+	if err != nil {
+		t.Fatalf("EncodeOUIDB: %v", err)
+	}
+	for range 5 {
+		if data, _ := EncodeOUIDB(db); !bytes.Equal(data, first) {
+			t.Fatal("EncodeOUIDB output differs between runs")
+		}
+	}
+}
+
+func TestOuiDBBuildInfo(t *testing.T) {
+	built := time.Date(2026, 1, 26, 0, 0, 0, 0, time.UTC)
+	db := &OuiDB{loadFunc: func() (map[string]*OuiBlock, OuiDBInfo) {
+		return map[string]*OuiBlock{}, OuiDBInfo{Version: 2, Built: built}
+	}}
+	if info := db.BuildInfo(); info.Version != 2 || !info.Built.Equal(built) {
+		t.Errorf("BuildInfo() before any lookup = %+v", info)
+	}
+	if info := OUITable.BuildInfo(); info.Version == 0 {
+		t.Errorf("OUITable.BuildInfo() = %+v, want a version", info)
+	}
+}
+
+// TestEmbeddedTable checks the fields of the embedded table. The checked-in
+// table is format version 1 until the next scheduled update rebuilds it, and
+// version 1 tables have no registries, normalized names, address parts,
+// removals or build information.
+func TestEmbeddedTable(t *testing.T) {
+	blocks, info, err := DecodeOUIDBInfo(ouiTableData)
+	if err != nil {
+		t.Fatalf("DecodeOUIDBInfo(embedded): %v", err)
+	}
+	if len(blocks) < 50000 {
+		t.Fatalf("embedded table has %d blocks", len(blocks))
+	}
+	block := blocks["001bc5000000/24"]
+	switch info.Version {
+	case 1:
+		t.Log("embedded table is format version 1; version 2 fields are empty until it is rebuilt")
+		if block.Registry != "" || block.OrgID != "" || !info.Built.IsZero() {
+			t.Errorf("version 1 table has version 2 fields: %+v, %+v", block, info)
+		}
+	case 2:
+		if block.Registry != RegistryMAL || block.OrgID == "" || block.City == "" || info.Built.IsZero() || len(info.Sources) == 0 {
+			t.Errorf("version 2 table is missing fields: %+v, %+v", block, info)
+		}
+	default:
+		t.Errorf("embedded table version %d", info.Version)
+	}
+}
+
+func TestDecodeOUIDBVersions(t *testing.T) {
+	// Version 2 readers skip fields they don't know about
+	var raw bytes.Buffer
+	raw.Write(ouiMagicV2[:])
+	binary.Write(&raw, binary.LittleEndian, int64(0))
+	binary.Write(&raw, binary.LittleEndian, uint16(0))
+	binary.Write(&raw, binary.LittleEndian, uint32(1))
+	raw.Write([]byte{0x00, 0x1b, 0xc5, 0, 0, 0, 0, 0})
+	raw.WriteByte(24)
+	raw.WriteByte(2)
+	raw.WriteByte(200)
+	writeString16(&raw, "future field")
+	raw.WriteByte(ouiFieldVendor)
+	writeString16(&raw, "Converging Systems Inc.")
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(raw.Bytes())
+	gz.Close()
+
+	blocks, info, err := DecodeOUIDBInfo(compressed.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeOUIDBInfo(unknown tag): %v", err)
+	}
+	if block := blocks["001bc5000000/24"]; block == nil || block.Vendor != "Converging Systems Inc." || !info.Built.IsZero() {
+		t.Errorf("decoded %+v with info %+v", block, info)
+	}
+
+	// Truncated input is an error rather than a partial table
+	if _, err := DecodeOUIDB(compressed.Bytes()[:len(compressed.Bytes())/2]); err == nil {
+		t.Error("DecodeOUIDB accepted truncated input")
+	}
+}
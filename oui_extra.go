@@ -1,6 +1,10 @@
 package mactracker
 
 // OUISkipPrefixes is a set of prefixes to skip to avoid bad lookups.
+//
+// The set is read once, on the first lookup, and later changes to the map
+// are ignored. Call SetOUISkipPrefixes to change the skip list afterwards, or
+// Resolver.SetSkipPrefixes for a single resolver.
 var OUISkipPrefixes = map[string]struct{}{
 	// Mask: 24
 	"000000000000/24": {}, // The zero OUI is registered to Xerox but is typically invalid input
@@ -0,0 +1,154 @@
+package mactracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Registration types recorded in the registration history.
+const (
+	RegistrationAdd    = "add"
+	RegistrationChange = "change"
+	RegistrationRemove = "remove" // The prefix was dropped from its registry
+	RegistrationReadd  = "re-add" // The prefix was listed again after a removal
+)
+
+// Registration is a single dated event in the history of a prefix, using the
+// same layout as the entries in data/macs.json.
+type Registration struct {
+	Prefix   string `json:"-"`
+	Date     string `json:"d"`
+	Type     string `json:"t"`
+	Source   string `json:"s,omitempty"`
+	Address  string `json:"a"`
+	Country  string `json:"c"`
+	Org      string `json:"o"`
+	Registry string `json:"r,omitempty"`
+}
+
+// OuiHistory holds the full registration history of every known prefix,
+// keyed by masked-prefix keys such as "000e02000000/24".
+type OuiHistory struct {
+	Prefixes map[string][]Registration
+	index    *ouiIndex[[]Registration]
+}
+
+// LoadHistory reads a registration history in the data/macs.json format.
+func LoadHistory(r io.Reader) (*OuiHistory, error) {
+	var prefixes map[string][]Registration
+	if err := json.NewDecoder(r).Decode(&prefixes); err != nil {
+		return nil, fmt.Errorf("decode history: %w", err)
+	}
+	return NewOuiHistory(prefixes), nil
+}
+
+// LoadHistoryFile reads a registration history from a data/macs.json file.
+func LoadHistoryFile(path string) (*OuiHistory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadHistory(f)
+}
+
+// NewOuiHistory builds a history from registration entries keyed by prefix.
+// Each prefix's entries are ordered by date; the map takes ownership of the slices.
+func NewOuiHistory(prefixes map[string][]Registration) *OuiHistory {
+	h := &OuiHistory{Prefixes: make(map[string][]Registration, len(prefixes))}
+	for key, regs := range prefixes {
+		key = strings.ToLower(key)
+		for i := range regs {
+			regs[i].Prefix = key
+			regs[i].Date = strings.TrimSpace(regs[i].Date)
+		}
+		slices.SortStableFunc(regs, func(a, b Registration) int {
+			return strings.Compare(a.Date, b.Date)
+		})
+		h.Prefixes[key] = regs
+	}
+	h.index = newOuiIndex(h.Prefixes)
+	return h
+}
+
+// Entries returns the full history of a single prefix key (e.g. "70b3d5c3c000/36"),
+// oldest first, or nil when the prefix has never been registered.
+func (h *OuiHistory) Entries(key string) []Registration {
+	return h.Prefixes[strings.ToLower(key)]
+}
+
+// Lookup returns the full history of the most-specific prefix containing the
+// MAC address string, oldest first, or nil when no prefix matches.
+func (h *OuiHistory) Lookup(s string) []Registration {
+	addr, err := ParseMAC(s)
+	if err != nil {
+		return nil
+	}
+	bits, width, ok := addrBits(addr)
+	if !ok {
+		return nil
+	}
+	skip := ouiSkip()
+	for i := h.index.first(bits, width); i >= 0; i = h.index.next(i, bits, width) {
+		e := &h.index.entries[i]
+		if _, skipped := skip[e.prefix]; skipped {
+			continue
+		}
+		return e.value
+	}
+	return nil
+}
+
+// LookupAt resolves a MAC address string to the registration that was in
+// effect on the given date. A more specific prefix only applies once it has
+// been registered and while it is not removed; otherwise the enclosing prefix
+// is used. Returns nil when the address is unparseable or nothing was
+// registered on that date.
+func (h *OuiHistory) LookupAt(s string, t time.Time) *Registration {
+	addr, err := ParseMAC(s)
+	if err != nil {
+		return nil
+	}
+	return h.LookupBytesAt(addr, t)
+}
+
+// LookupBytesAt is like LookupAt but accepts a raw byte-slice address (6 or 8 bytes).
+func (h *OuiHistory) LookupBytesAt(addr []byte, t time.Time) *Registration {
+	bits, width, ok := addrBits(addr)
+	if !ok {
+		return nil
+	}
+	day := t.Format("2006-01-02")
+	skip := ouiSkip()
+	for i := h.index.first(bits, width); i >= 0; i = h.index.next(i, bits, width) {
+		e := &h.index.entries[i]
+		if _, skipped := skip[e.prefix]; skipped {
+			continue
+		}
+		if reg := registrationAt(e.value, day); reg != nil {
+			return reg
+		}
+	}
+	return nil
+}
+
+// registrationAt returns the latest entry dated on or before day, or nil when
+// there is none or it is a removal.
+func registrationAt(regs []Registration, day string) *Registration {
+	var res *Registration
+	for i := range regs {
+		if regs[i].Date > day {
+			break
+		}
+		res = &regs[i]
+	}
+	if res != nil && res.Type == RegistrationRemove {
+		return nil
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package mactracker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testHistoryJSON = `{
+  "70b3d5000000/24": [
+    {"d": "2014-01-09", "t": "add", "a": "445 HOES LANE PISCATAWAY NJ 08854", "c": "UNITED STATES", "o": "IEEE REGISTRATION AUTHORITY"}
+  ],
+  "70b3d5c3c000/36": [
+    {"d": "2019-06-01", "t": "change", "a": "5401 N SAM HOUSTON PKWY W HOUSTON TEXAS US 77086", "c": "US", "o": "PEEK TRAFFIC CORPORATION", "s": "ieee-oui36.csv"},
+    {"d": "2015-03-01", "t": "add", "a": "5401 N SAM HOUSTON PKWY W HOUSTON TEXAS US 77086", "c": "US", "o": "PEEK TRAFFIC", "s": "ieee-oui36.csv"}
+  ],
+  "70b3d5c3d000/36": [
+    {"d": "2016-02-01", "t": "add", "a": "", "c": "DE", "o": "GONE GMBH", "s": "ieee-oui36.csv"},
+    {"d": "2018-07-01", "t": "remove", "a": "", "c": "DE", "o": "GONE GMBH", "s": "ieee-oui36.csv"},
+    {"d": "2021-03-01", "t": "re-add", "a": "", "c": "DE", "o": "BACK GMBH", "s": "ieee-oui36.csv"}
+  ],
+  "000000000000/24": [
+    {"d": "1998-04-22", "t": "add", "a": "", "c": "", "o": "XEROX CORPORATION"}
+  ]
+}`
+
+func loadTestHistory(t *testing.T) *OuiHistory {
+	t.Helper()
+	h, err := LoadHistory(strings.NewReader(testHistoryJSON))
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	return h
+}
+
+func TestHistoryLookupAt(t *testing.T) {
+	h := loadTestHistory(t)
+
+	tests := []struct {
+		mac  string
+		date string
+		want string
+	}{
+		{mac: "70:b3:d5:c3:c0:01", date: "2013-12-31", want: ""},
+		{mac: "70:b3:d5:c3:c0:01", date: "2014-01-09", want: "IEEE REGISTRATION AUTHORITY"},
+		{mac: "70:b3:d5:c3:c0:01", date: "2015-03-01", want: "PEEK TRAFFIC"},
+		{mac: "70:b3:d5:c3:c0:01", date: "2019-05-31", want: "PEEK TRAFFIC"},
+		{mac: "70:b3:d5:c3:c0:01", date: "2024-01-01", want: "PEEK TRAFFIC CORPORATION"},
+		{mac: "70:b3:d5:00:00:01", date: "2024-01-01", want: "IEEE REGISTRATION AUTHORITY"},
+		{mac: "00:00:00:00:00:01", date: "2024-01-01", want: ""},
+		// Removed prefixes fall back to the enclosing registration until re-added
+		{mac: "70:b3:d5:c3:d0:01", date: "2017-01-01", want: "GONE GMBH"},
+		{mac: "70:b3:d5:c3:d0:01", date: "2018-07-01", want: "IEEE REGISTRATION AUTHORITY"},
+		{mac: "70:b3:d5:c3:d0:01", date: "2021-03-01", want: "BACK GMBH"},
+		{mac: "invalid", date: "2024-01-01", want: ""},
+	}
+	for _, test := range tests {
+		day, _ := time.Parse("2006-01-02", test.date)
+		got := ""
+		if reg := h.LookupAt(test.mac, day); reg != nil {
+			got = reg.Org
+		}
+		if got != test.want {
+			t.Errorf("LookupAt(%q, %s) = %q, want %q", test.mac, test.date, got, test.want)
+		}
+	}
+}
+
+func TestHistoryEntries(t *testing.T) {
+	h := loadTestHistory(t)
+
+	regs := h.Entries("70B3D5C3C000/36")
+	if len(regs) != 2 || regs[0].Type != RegistrationAdd || regs[1].Type != RegistrationChange {
+		t.Fatalf("Entries returned %+v, want add then change", regs)
+	}
+	if regs[0].Prefix != "70b3d5c3c000/36" {
+		t.Errorf("Entries prefix = %q, want %q", regs[0].Prefix, "70b3d5c3c000/36")
+	}
+
+	regs = h.Lookup("70:b3:d5:c3:c0:01")
+	if len(regs) != 2 || regs[0].Prefix != "70b3d5c3c000/36" {
+		t.Errorf("Lookup returned %+v, want the /36 history", regs)
+	}
+	if regs := h.Entries("8c1f64000000/24"); regs != nil {
+		t.Errorf("Entries for unknown prefix = %+v, want nil", regs)
+	}
+}
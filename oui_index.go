@@ -0,0 +1,225 @@
+package mactracker
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ouiPrefix is the comparable form of a "hex/mask" block key. The prefix bits
+// are left-aligned in a uint64 so that 48-bit and 64-bit addresses share one
+// keyspace.
+type ouiPrefix struct {
+	bits uint64
+	mask uint8
+}
+
+// prefixMask returns a uint64 with the leading ones bits set.
+func prefixMask(ones int) uint64 {
+	return ^uint64(0) << (64 - uint(ones))
+}
+
+// last returns the highest address covered by the prefix.
+func (p ouiPrefix) last() uint64 {
+	return p.bits | ^prefixMask(int(p.mask))
+}
+
+// parsePrefixKey parses a block key such as "70b3d5c3c000/36". Keys with bits
+// set beyond the mask can never match an address and are rejected.
+func parsePrefixKey(key string) (ouiPrefix, bool) {
+	hexPart, maskPart, found := strings.Cut(key, "/")
+	if !found || len(hexPart) == 0 || len(hexPart) > 16 {
+		return ouiPrefix{}, false
+	}
+	mask, err := strconv.Atoi(maskPart)
+	if err != nil || mask < 0 || mask > len(hexPart)*4 {
+		return ouiPrefix{}, false
+	}
+	var bits uint64
+	for i := 0; i < len(hexPart); i++ {
+		n, ok := fromHexChar(hexPart[i])
+		if !ok {
+			return ouiPrefix{}, false
+		}
+		bits = bits<<4 | uint64(n)
+	}
+	bits <<= 64 - 4*uint(len(hexPart))
+	if bits&^prefixMask(mask) != 0 {
+		return ouiPrefix{}, false
+	}
+	return ouiPrefix{bits: bits, mask: uint8(mask)}, true
+}
+
+// fromHexChar converts a hex character into its value.
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// addrBits left-aligns a 6 or 8-byte address in a uint64 and returns its width
+// in bits. Addresses of any other length are not matched against the tables.
+func addrBits(addr []byte) (uint64, int, bool) {
+	if len(addr) != 6 && len(addr) != 8 {
+		return 0, 0, false
+	}
+	var bits uint64
+	for i := range 8 {
+		bits <<= 8
+		if i < len(addr) {
+			bits |= uint64(addr[i])
+		}
+	}
+	return bits, len(addr) * 8, true
+}
+
+// compilePrefixKeys converts a set of "hex/mask" keys into a prefix set,
+// dropping keys that cannot be parsed.
+func compilePrefixKeys(keys map[string]struct{}) map[ouiPrefix]struct{} {
+	res := make(map[ouiPrefix]struct{}, len(keys))
+	for k := range keys {
+		if p, ok := parsePrefixKey(k); ok {
+			res[p] = struct{}{}
+		}
+	}
+	return res
+}
+
+// ouiIndex is a longest-prefix-match index over a set of prefixes of any
+// width. Entries are sorted by their first address and each entry records the
+// nearest entry that fully contains it, so a lookup is a binary search
+// followed by a short walk up the containment chain.
+type ouiIndex[T any] struct {
+	entries []ouiIndexEntry[T]
+}
+
+type ouiIndexEntry[T any] struct {
+	prefix ouiPrefix
+	last   uint64
+	parent int
+	value  T
+}
+
+// newOuiIndex builds an index from a map keyed by "hex/mask" strings.
+// Keys that cannot be parsed are ignored.
+func newOuiIndex[T any](m map[string]T) *ouiIndex[T] {
+	x := &ouiIndex[T]{entries: make([]ouiIndexEntry[T], 0, len(m))}
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		p, ok := parsePrefixKey(k)
+		if !ok {
+			continue
+		}
+		x.entries = append(x.entries, ouiIndexEntry[T]{prefix: p, last: p.last(), value: m[k]})
+	}
+
+	// Sort by first address, with wider prefixes ahead of the narrower
+	// prefixes they contain. The entries were added in key order and the
+	// sort is stable, so duplicate prefixes (e.g. "00/8" and "0000/8") stay
+	// in key order and lookups return the later key first.
+	slices.SortStableFunc(x.entries, func(a, b ouiIndexEntry[T]) int {
+		if a.prefix.bits != b.prefix.bits {
+			if a.prefix.bits < b.prefix.bits {
+				return -1
+			}
+			return 1
+		}
+		return int(a.prefix.mask) - int(b.prefix.mask)
+	})
+
+	// Prefixes are either nested or disjoint, so a stack of open prefixes
+	// gives each entry its nearest containing parent.
+	stack := make([]int, 0, 8)
+	for i := range x.entries {
+		e := &x.entries[i]
+		for len(stack) > 0 && x.entries[stack[len(stack)-1]].last < e.prefix.bits {
+			stack = stack[:len(stack)-1]
+		}
+		e.parent = -1
+		if len(stack) > 0 {
+			e.parent = stack[len(stack)-1]
+		}
+		stack = append(stack, i)
+	}
+	return x
+}
+
+// matches reports whether the entry covers an address of the given width.
+func (e *ouiIndexEntry[T]) matches(bits uint64, width int) bool {
+	return int(e.prefix.mask) <= width && e.prefix.bits <= bits && bits <= e.last
+}
+
+// first returns the position of the most specific entry containing the
+// address, or -1 when there is none.
+func (x *ouiIndex[T]) first(bits uint64, width int) int {
+	lo, hi := 0, len(x.entries)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if x.entries[mid].prefix.bits <= bits {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return x.climb(lo-1, bits, width)
+}
+
+// next returns the position of the next less specific entry containing the
+// address, or -1 when there is none.
+func (x *ouiIndex[T]) next(i int, bits uint64, width int) int {
+	return x.climb(x.entries[i].parent, bits, width)
+}
+
+func (x *ouiIndex[T]) climb(i int, bits uint64, width int) int {
+	for i >= 0 && !x.entries[i].matches(bits, width) {
+		i = x.entries[i].parent
+	}
+	return i
+}
+
+// overlapping iterates over the positions of every entry that contains or is
+// contained by p, in index order.
+func (x *ouiIndex[T]) overlapping(p ouiPrefix) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		last := p.last()
+		lo, _ := slices.BinarySearchFunc(x.entries, p.bits, func(e ouiIndexEntry[T], bits uint64) int {
+			return cmp.Compare(e.prefix.bits, bits)
+		})
+
+		// Entries starting before p overlap it only by containing it, and
+		// those form a single chain of parents.
+		var outer []int
+		for i := x.climbTo(lo-1, p.bits); i >= 0; i = x.entries[i].parent {
+			outer = append(outer, i)
+		}
+		for _, i := range slices.Backward(outer) {
+			if !yield(i) {
+				return
+			}
+		}
+
+		// Entries starting within p overlap it
+		for i := lo; i < len(x.entries) && x.entries[i].prefix.bits <= last; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// climbTo walks up the containment chain from i to the first entry that
+// covers bits, regardless of address width.
+func (x *ouiIndex[T]) climbTo(i int, bits uint64) int {
+	for i >= 0 && x.entries[i].last < bits {
+		i = x.entries[i].parent
+	}
+	return i
+}
@@ -0,0 +1,58 @@
+package mactracker
+
+import (
+	"errors"
+	"net/netip"
+)
+
+var (
+	// ErrNotIPv6 is returned for input that is not an IPv6 address.
+	ErrNotIPv6 = errors.New("not an IPv6 address")
+
+	// ErrNotEUI64 is returned when an IPv6 interface identifier was not
+	// derived from a MAC address, such as a temporary (privacy extension),
+	// stable-privacy, DHCPv6 or manually assigned address.
+	ErrNotEUI64 = errors.New("interface identifier is not derived from a MAC address")
+)
+
+// MACFromIPv6 recovers the MAC address from an IPv6 address whose interface
+// identifier is a modified EUI-64 (RFC 4291 appendix A): the MAC split by
+// ff:fe with the universal/local bit inverted. Returns ErrNotEUI64 when the
+// interface identifier was generated some other way.
+func MACFromIPv6(ip netip.Addr) (OuiHardwareAddr, error) {
+	if !ip.Is6() || ip.Is4In6() {
+		return nil, ErrNotIPv6
+	}
+	b := ip.As16()
+	iid := b[8:]
+	if iid[3] != 0xff || iid[4] != 0xfe {
+		return nil, ErrNotEUI64
+	}
+	return OuiHardwareAddr{iid[0] ^ 0x02, iid[1], iid[2], iid[5], iid[6], iid[7]}, nil
+}
+
+// LookupIPv6 parses an IPv6 address string (zones such as "%eth0" are
+// allowed), recovers the MAC address from its EUI-64 interface identifier, and
+// resolves it using the DefaultResolver. The MAC is returned even when no block
+// matches; ErrNotIPv6 and ErrNotEUI64 report input that has no MAC to recover.
+func LookupIPv6(s string) (*OuiBlock, OuiHardwareAddr, error) {
+	return DefaultResolver().LookupIPv6(s)
+}
+
+// LookupIPv6 is like the package-level LookupIPv6 but uses the resolver's tables.
+func (r *Resolver) LookupIPv6(s string) (*OuiBlock, OuiHardwareAddr, error) {
+	ip, err := netip.ParseAddr(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.LookupIPv6Addr(ip)
+}
+
+// LookupIPv6Addr is like LookupIPv6 but accepts a parsed address.
+func (r *Resolver) LookupIPv6Addr(ip netip.Addr) (*OuiBlock, OuiHardwareAddr, error) {
+	mac, err := MACFromIPv6(ip)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.LookupBytes(mac), mac, nil
+}
@@ -0,0 +1,56 @@
+package mactracker
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestLookupIPv6(t *testing.T) {
+	tests := []struct {
+		ip     string
+		mac    string
+		vendor string
+		err    error
+	}{
+		{ip: "fe80::21b:c5ff:fe00:203", mac: "00:1b:c5:00:02:03", vendor: "Converging Systems Inc."},
+		{ip: "fe80::21b:c5ff:fe00:203%eth0", mac: "00:1b:c5:00:02:03", vendor: "Converging Systems Inc."},
+		{ip: "2001:db8::5054:ff:fe12:3456", mac: "52:54:00:12:34:56"},
+		{ip: "2001:db8:1:2:250:56ff:fe12:3456", mac: "00:50:56:12:34:56", vendor: "VMware, Inc."},
+		{ip: "2001:db8::a1b2:c3d4:e5f6:789", err: ErrNotEUI64},
+		{ip: "192.0.2.1", err: ErrNotIPv6},
+		{ip: "::ffff:192.0.2.1", err: ErrNotIPv6},
+	}
+	for _, test := range tests {
+		block, mac, err := LookupIPv6(test.ip)
+		if !errors.Is(err, test.err) {
+			t.Errorf("LookupIPv6(%q) error = %v, want %v", test.ip, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if mac.String() != test.mac {
+			t.Errorf("LookupIPv6(%q) mac = %s, want %s", test.ip, mac, test.mac)
+		}
+		vendor := ""
+		if block != nil {
+			vendor = block.Vendor
+		}
+		if vendor != test.vendor {
+			t.Errorf("LookupIPv6(%q) vendor = %q, want %q", test.ip, vendor, test.vendor)
+		}
+	}
+
+	if _, _, err := LookupIPv6("not an address"); err == nil {
+		t.Error("LookupIPv6 accepted an invalid address")
+	}
+}
+
+func TestMACFromIPv6(t *testing.T) {
+	// The universal/local bit is inverted in the interface identifier
+	mac, err := MACFromIPv6(netip.MustParseAddr("fe80::ff:fe00:1"))
+	if err != nil || mac.String() != "02:00:00:00:00:01" || !mac.HasLAA() {
+		t.Errorf("MACFromIPv6 = %s, %v, want locally administered 02:00:00:00:00:01", mac, err)
+	}
+}
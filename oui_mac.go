@@ -0,0 +1,194 @@
+package mactracker
+
+import (
+	"net"
+)
+
+// MAC48 is a MAC-48/EUI-48 address stored by value. Unlike OuiHardwareAddr it
+// is comparable, so it can be used as a map key, and it parses and converts
+// without allocating.
+type MAC48 [6]byte
+
+// EUI64 is an EUI-64 address stored by value, the 8-byte counterpart of MAC48.
+type EUI64 [8]byte
+
+// ParseMAC48 parses a 6-byte address in any of the notations accepted by
+// ParseMAC without allocating. Unlike ParseMAC it requires exactly 12 hex
+// digits; errors are a *MACSyntaxError, returned with the zero MAC48.
+func ParseMAC48(s string) (MAC48, error) {
+	var m MAC48
+	if err := parseMACInto(s, m[:]); err != nil {
+		return MAC48{}, err
+	}
+	return m, nil
+}
+
+// ParseEUI64 parses an 8-byte address in any of the notations accepted by
+// ParseMAC without allocating. It requires exactly 16 hex digits; errors are
+// a *MACSyntaxError, returned with the zero EUI64.
+func ParseEUI64(s string) (EUI64, error) {
+	var e EUI64
+	if err := parseMACInto(s, e[:]); err != nil {
+		return EUI64{}, err
+	}
+	return e, nil
+}
+
+// parseMACInto decodes the hex digits of s into dst, skipping the same
+// separators as ParseMAC. The digits must fill dst exactly.
+func parseMACInto(s string, dst []byte) error {
+	if s == "" {
+		return &MACSyntaxError{Input: s, Offset: -1, Err: ErrMACEmpty}
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case ':', '-', '.', ' ', '_', '\t', '[', ']':
+			continue
+		}
+		v, ok := fromHexChar(c)
+		if !ok {
+			return &MACSyntaxError{Input: s, Offset: i, Err: ErrMACInvalidChar}
+		}
+		if n == 2*len(dst) {
+			return &MACSyntaxError{Input: s, Offset: -1, Err: ErrMACLength}
+		}
+		if n%2 == 0 {
+			dst[n/2] = v << 4
+		} else {
+			dst[n/2] |= v
+		}
+		n++
+	}
+	if n != 2*len(dst) {
+		return &MACSyntaxError{Input: s, Offset: -1, Err: ErrMACLength}
+	}
+	return nil
+}
+
+// MAC48FromSlice returns the MAC48 held in a 6-byte slice, such as an
+// OuiHardwareAddr or net.HardwareAddr. It reports false for other lengths.
+func MAC48FromSlice(b []byte) (MAC48, bool) {
+	var m MAC48
+	if len(b) != len(m) {
+		return m, false
+	}
+	copy(m[:], b)
+	return m, true
+}
+
+// EUI64FromSlice returns the EUI64 held in an 8-byte slice. It reports false
+// for other lengths.
+func EUI64FromSlice(b []byte) (EUI64, bool) {
+	var e EUI64
+	if len(b) != len(e) {
+		return e, false
+	}
+	copy(e[:], b)
+	return e, true
+}
+
+// IsZero reports whether m is the all-zero address.
+func (m MAC48) IsZero() bool {
+	return m == MAC48{}
+}
+
+// Addr returns the address as a newly allocated OuiHardwareAddr.
+func (m MAC48) Addr() OuiHardwareAddr {
+	return OuiHardwareAddr(m[:])
+}
+
+// HardwareAddr returns the address as a newly allocated net.HardwareAddr.
+func (m MAC48) HardwareAddr() net.HardwareAddr {
+	return net.HardwareAddr(m[:])
+}
+
+// String returns the colon-separated lowercase hex form, e.g. "00:1b:c5:00:02:03".
+func (m MAC48) String() string {
+	var buf [17]byte
+	return string(appendMAC(buf[:0], m[:]))
+}
+
+// AppendText implements encoding.TextAppender.
+func (m MAC48) AppendText(b []byte) ([]byte, error) {
+	return appendMAC(b, m[:]), nil
+}
+
+// MarshalText implements encoding.TextMarshaler using the String form.
+func (m MAC48) MarshalText() ([]byte, error) {
+	return appendMAC(make([]byte, 0, 17), m[:]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text sets the
+// zero address.
+func (m *MAC48) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = MAC48{}
+		return nil
+	}
+	var v MAC48
+	if err := parseMACInto(string(text), v[:]); err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// IsZero reports whether e is the all-zero address.
+func (e EUI64) IsZero() bool {
+	return e == EUI64{}
+}
+
+// Addr returns the address as a newly allocated OuiHardwareAddr.
+func (e EUI64) Addr() OuiHardwareAddr {
+	return OuiHardwareAddr(e[:])
+}
+
+// HardwareAddr returns the address as a newly allocated net.HardwareAddr.
+func (e EUI64) HardwareAddr() net.HardwareAddr {
+	return net.HardwareAddr(e[:])
+}
+
+// String returns the colon-separated lowercase hex form, e.g. "00:1b:c5:ff:fe:00:02:03".
+func (e EUI64) String() string {
+	var buf [23]byte
+	return string(appendMAC(buf[:0], e[:]))
+}
+
+// AppendText implements encoding.TextAppender.
+func (e EUI64) AppendText(b []byte) ([]byte, error) {
+	return appendMAC(b, e[:]), nil
+}
+
+// MarshalText implements encoding.TextMarshaler using the String form.
+func (e EUI64) MarshalText() ([]byte, error) {
+	return appendMAC(make([]byte, 0, 23), e[:]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text sets the
+// zero address.
+func (e *EUI64) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*e = EUI64{}
+		return nil
+	}
+	var v EUI64
+	if err := parseMACInto(string(text), v[:]); err != nil {
+		return err
+	}
+	*e = v
+	return nil
+}
+
+// appendMAC appends the colon-separated lowercase hex form of addr to b.
+func appendMAC(b, addr []byte) []byte {
+	const digits = "0123456789abcdef"
+	for i, v := range addr {
+		if i > 0 {
+			b = append(b, ':')
+		}
+		b = append(b, digits[v>>4], digits[v&0x0f])
+	}
+	return b
+}
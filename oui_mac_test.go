@@ -0,0 +1,143 @@
+package mactracker
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestParseMAC48(t *testing.T) {
+	want := MAC48{0x00, 0x1b, 0xc5, 0x00, 0x02, 0x03}
+	for _, s := range []string{"00:1b:c5:00:02:03", "00-1B-C5-00-02-03", "001b.c500.0203", "001bc5000203", "[00 1b c5 00 02 03]"} {
+		m, err := ParseMAC48(s)
+		if err != nil {
+			t.Errorf("ParseMAC48(%q) error = %v", s, err)
+			continue
+		}
+		if m != want {
+			t.Errorf("ParseMAC48(%q) = %s, want %s", s, m, want)
+		}
+	}
+
+	for _, test := range []struct {
+		input string
+		err   error
+	}{
+		{input: "", err: ErrMACEmpty},
+		{input: "00:1b:c5:00:02", err: ErrMACLength},
+		{input: "00:1b:c5:00:02:03:04", err: ErrMACLength},
+		{input: "00:1b:c5:00:02:0", err: ErrMACLength},
+		{input: "00:1b:c5:00:02:0g", err: ErrMACInvalidChar},
+	} {
+		m, err := ParseMAC48(test.input)
+		if !errors.Is(err, test.err) {
+			t.Errorf("ParseMAC48(%q) error = %v, want %v", test.input, err, test.err)
+		}
+		if m != (MAC48{}) {
+			t.Errorf("ParseMAC48(%q) = %s on error, want the zero MAC48", test.input, m)
+		}
+		if e, _ := ParseEUI64(test.input + ":05:06"); e != (EUI64{}) {
+			t.Errorf("ParseEUI64(%q) = %s on error, want the zero EUI64", test.input+":05:06", e)
+		}
+	}
+
+	e, err := ParseEUI64("00:1b:c5:ff:fe:00:02:03")
+	if err != nil {
+		t.Fatalf("ParseEUI64 error = %v", err)
+	}
+	if e.String() != "00:1b:c5:ff:fe:00:02:03" {
+		t.Errorf("ParseEUI64 = %s", e)
+	}
+	if e, err := ParseEUI64("00:1b:c5:00:02:03"); !errors.Is(err, ErrMACLength) || e != (EUI64{}) {
+		t.Errorf("ParseEUI64 of a 6-byte address = %s, %v, want the zero EUI64 and %v", e, err, ErrMACLength)
+	}
+}
+
+func TestParseMAC48Allocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := ParseMAC48("00:1b:c5:00:02:03"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ParseEUI64("001b.c5ff.fe00.0203"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("ParseMAC48 allocated %.0f times per run, want 0", allocs)
+	}
+}
+
+func TestMAC48Conversions(t *testing.T) {
+	m := MAC48{0x00, 0x1b, 0xc5, 0x00, 0x02, 0x03}
+
+	addr := m.Addr()
+	if addr.String() != m.String() {
+		t.Errorf("Addr() = %s, want %s", addr, m)
+	}
+	addr[0] = 0xff
+	if m[0] != 0x00 {
+		t.Error("Addr() aliases the MAC48")
+	}
+
+	hw, _ := net.ParseMAC("00:1b:c5:00:02:03")
+	if got, ok := MAC48FromSlice(hw); !ok || got != m {
+		t.Errorf("MAC48FromSlice(%s) = %s, %v", hw, got, ok)
+	}
+	if m.HardwareAddr().String() != hw.String() {
+		t.Errorf("HardwareAddr() = %s, want %s", m.HardwareAddr(), hw)
+	}
+	if _, ok := MAC48FromSlice(hw[:5]); ok {
+		t.Error("MAC48FromSlice accepted a 5-byte slice")
+	}
+	if _, ok := EUI64FromSlice(OuiHardwareAddr(hw)); ok {
+		t.Error("EUI64FromSlice accepted a 6-byte slice")
+	}
+
+	if block := LookupBytes(m[:]); block == nil || block.Vendor != "Converging Systems Inc." {
+		t.Errorf("LookupBytes(%s) = %+v", m, block)
+	}
+
+	// Comparable values work as map keys
+	seen := map[MAC48]int{m: 1}
+	if other, _ := ParseMAC48("00-1b-c5-00-02-03"); seen[other] != 1 {
+		t.Error("equal MAC48 values are not equal map keys")
+	}
+}
+
+func TestMAC48Text(t *testing.T) {
+	type record struct {
+		MAC  MAC48         `json:"mac"`
+		EUI  EUI64         `json:"eui"`
+		Keys map[MAC48]int `json:"keys"`
+	}
+	in := record{
+		MAC:  MAC48{0x00, 0x1b, 0xc5, 0x00, 0x02, 0x03},
+		EUI:  EUI64{0x00, 0x1b, 0xc5, 0xff, 0xfe, 0x00, 0x02, 0x03},
+		Keys: map[MAC48]int{{0x02, 0, 0, 0, 0, 1}: 7},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"mac":"00:1b:c5:00:02:03","eui":"00:1b:c5:ff:fe:00:02:03","keys":{"02:00:00:00:00:01":7}}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	var out record
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.MAC != in.MAC || out.EUI != in.EUI || out.Keys[MAC48{0x02, 0, 0, 0, 0, 1}] != 7 {
+		t.Errorf("Unmarshal = %+v, want %+v", out, in)
+	}
+
+	var m MAC48
+	if err := m.UnmarshalText([]byte("00:1b:c5")); !errors.Is(err, ErrMACLength) {
+		t.Errorf("UnmarshalText error = %v, want %v", err, ErrMACLength)
+	}
+	if err := m.UnmarshalText(nil); err != nil || !m.IsZero() {
+		t.Errorf("UnmarshalText(nil) = %s, %v", m, err)
+	}
+}
@@ -0,0 +1,51 @@
+package mactracker
+
+// OUITableMulticast names well-known group (multicast and broadcast) addresses
+// and the reserved virtual-router ranges, with the protocol that uses them and
+// the standard that defines them. These addresses otherwise resolve to nothing
+// or to whoever owns the OUI, so the DefaultResolver consults this table first.
+var OUITableMulticast = OuiDB{Blocks: map[string]*OuiBlock{
+	// Mask: 48
+	"ffffffffffff/48": {Oui: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, Mask: 48, Vendor: "Broadcast", Protocol: "Broadcast", Standard: "IEEE 802"},
+	"0180c2000000/48": {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x00}, Mask: 48, Vendor: "Bridge Group Address", Protocol: "STP", Standard: "IEEE 802.1D"},
+	"0180c2000001/48": {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x01}, Mask: 48, Vendor: "MAC Control (Pause)", Protocol: "Ethernet Flow Control", Standard: "IEEE 802.3x"},
+	"0180c2000002/48": {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x02}, Mask: 48, Vendor: "Slow Protocols", Protocol: "LACP", Standard: "IEEE 802.3ad"},
+	"0180c2000003/48": {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x03}, Mask: 48, Vendor: "Port Access Entity", Protocol: "EAPOL", Standard: "IEEE 802.1X"},
+	"0180c2000008/48": {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x08}, Mask: 48, Vendor: "Provider Bridge Group Address", Protocol: "STP", Standard: "IEEE 802.1ad"},
+	"0180c200000e/48": {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x0e}, Mask: 48, Vendor: "Nearest Bridge", Protocol: "LLDP", Standard: "IEEE 802.1AB"},
+	"0180c2000020/48": {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x20}, Mask: 48, Vendor: "MMRP Address", Protocol: "MMRP", Standard: "IEEE 802.1Q"},
+	"0180c2000021/48": {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x21}, Mask: 48, Vendor: "MVRP Address", Protocol: "MVRP", Standard: "IEEE 802.1Q"},
+	"011b19000000/48": {Oui: []byte{0x01, 0x1b, 0x19, 0x00, 0x00, 0x00}, Mask: 48, Vendor: "PTP Primary Address", Protocol: "PTP", Standard: "IEEE 1588"},
+	"01000ccccccc/48": {Oui: []byte{0x01, 0x00, 0x0c, 0xcc, 0xcc, 0xcc}, Mask: 48, Vendor: "Cisco Discovery", Protocol: "CDP/VTP/DTP/PAgP/UDLD", Standard: "Cisco"},
+	"01000ccccccd/48": {Oui: []byte{0x01, 0x00, 0x0c, 0xcc, 0xcc, 0xcd}, Mask: 48, Vendor: "Cisco Shared Spanning Tree", Protocol: "PVST+", Standard: "Cisco"},
+	"01000cdddddd/48": {Oui: []byte{0x01, 0x00, 0x0c, 0xdd, 0xdd, 0xdd}, Mask: 48, Vendor: "Cisco Group Management", Protocol: "CGMP", Standard: "Cisco"},
+
+	// Mask: 44
+	"0180c2000000/44": {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x00}, Mask: 44, Vendor: "IEEE 802.1 Reserved", Protocol: "Link-local control", Standard: "IEEE 802.1Q"},
+	"0180c2000030/44": {Oui: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x30}, Mask: 44, Vendor: "CFM Group Address", Protocol: "CFM", Standard: "IEEE 802.1ag"},
+
+	// Mask: 40
+	"00005e000100/40": {Oui: []byte{0x00, 0x00, 0x5e, 0x00, 0x01, 0x00}, Mask: 40, Vendor: "VRRP Virtual Router (IPv4)", Protocol: "VRRP", Standard: "RFC 5798"},
+	"00005e000200/40": {Oui: []byte{0x00, 0x00, 0x5e, 0x00, 0x02, 0x00}, Mask: 40, Vendor: "VRRP Virtual Router (IPv6)", Protocol: "VRRP", Standard: "RFC 5798"},
+	"00000c07ac00/40": {Oui: []byte{0x00, 0x00, 0x0c, 0x07, 0xac, 0x00}, Mask: 40, Vendor: "HSRP Virtual Router", Protocol: "HSRP", Standard: "RFC 2281"},
+
+	// Mask: 36
+	"00000c9ff000/36": {Oui: []byte{0x00, 0x00, 0x0c, 0x9f, 0xf0, 0x00}, Mask: 36, Vendor: "HSRPv2 Virtual Router", Protocol: "HSRP", Standard: "Cisco HSRP version 2"},
+
+	// Mask: 25
+	"01005e000000/25": {Oui: []byte{0x01, 0x00, 0x5e, 0x00, 0x00, 0x00}, Mask: 25, Vendor: "IPv4 Multicast", Protocol: "IPv4 multicast", Standard: "RFC 1112"},
+	"01005e800000/25": {Oui: []byte{0x01, 0x00, 0x5e, 0x80, 0x00, 0x00}, Mask: 25, Vendor: "IANA Reserved Multicast", Protocol: "Reserved", Standard: "RFC 7042"},
+
+	// Mask: 16
+	"333300000000/16": {Oui: []byte{0x33, 0x33, 0x00, 0x00, 0x00, 0x00}, Mask: 16, Vendor: "IPv6 Multicast", Protocol: "IPv6 multicast", Standard: "RFC 2464"},
+}}
+
+// LookupMulticast searches only the table of well-known group and reserved
+// addresses. Returns nil when the address is unparseable or not listed.
+func LookupMulticast(s string) *OuiBlock {
+	addr, err := ParseMAC(s)
+	if err != nil {
+		return nil
+	}
+	return OUITableMulticast.Lookup(addr)
+}
@@ -0,0 +1,328 @@
+package mactracker
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Override files describe private or unofficial registrations that are
+// layered above the IEEE table. Both formats carry the same fields:
+//
+//	prefix   required  leading hex digits in any MAC notation ("d0:c9:07", "70b3d5c3c"),
+//	                   optionally followed by "/mask"
+//	mask     optional  CIDR mask width; defaults to 4 bits per hex digit in prefix
+//	vendor   required  organization name
+//	added    optional  registration date as YYYY-MM-DD
+//	country  optional  two-letter country code
+//	address  optional  organization address
+//	private  optional  true for private or unofficial registrations
+//	virtual  optional  virtualization platform name (e.g. "VMware")
+//
+// CSV files start with a header row naming the columns, in any order. Lines
+// starting with # are comments:
+//
+//	prefix,vendor,added,private
+//	d0:c9:07,Govee,2023-12-14,true
+//	70:b3:d5:c3:c/36,Lab Sensor,,true
+//
+// JSON files hold an array of objects:
+//
+//	[{"prefix": "d0:c9:07", "vendor": "Govee", "added": "2023-12-14", "private": true}]
+
+// OverrideRecord is a single registration in an override file.
+type OverrideRecord struct {
+	Prefix  string `json:"prefix"`
+	Mask    int    `json:"mask,omitempty"`
+	Vendor  string `json:"vendor"`
+	Added   string `json:"added,omitempty"`
+	Country string `json:"country,omitempty"`
+	Address string `json:"address,omitempty"`
+	Private bool   `json:"private,omitempty"`
+	Virtual string `json:"virtual,omitempty"`
+}
+
+// OverrideError reports an invalid record in an override file.
+type OverrideError struct {
+	File string // File name, when loaded with LoadOverridesFile
+	Line int    // 1-based line of the offending record
+	Err  error
+}
+
+func (e *OverrideError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *OverrideError) Unwrap() error {
+	return e.Err
+}
+
+// LoadOverridesFile loads an override table from a .csv or .json file.
+// Errors for individual records are reported as *OverrideError.
+func LoadOverridesFile(path string) (*OuiDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var db *OuiDB
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		db, err = LoadOverridesCSV(f)
+	case ".json":
+		db, err = LoadOverridesJSON(f)
+	default:
+		return nil, fmt.Errorf("%s: unsupported override file type %q", path, ext)
+	}
+	if oe, ok := err.(*OverrideError); ok {
+		oe.File = path
+	}
+	return db, err
+}
+
+// LoadOverridesCSV loads an override table from CSV with a header row.
+func LoadOverridesCSV(r io.Reader) (*OuiDB, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, &OverrideError{Line: 1, Err: errors.New("missing header row")}
+		}
+		return nil, csvOverrideError(err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "prefix", "mask", "vendor", "added", "country", "address", "private", "virtual":
+		default:
+			line, _ := reader.FieldPos(i)
+			return nil, &OverrideError{Line: line, Err: fmt.Errorf("unknown column %q", name)}
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"prefix", "vendor"} {
+		if _, ok := columns[name]; !ok {
+			line, _ := reader.FieldPos(0)
+			return nil, &OverrideError{Line: line, Err: fmt.Errorf("missing %q column", name)}
+		}
+	}
+
+	var records []OverrideRecord
+	var lines []int
+	for {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, csvOverrideError(err)
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+
+		o := OverrideRecord{
+			Prefix:  field("prefix"),
+			Vendor:  field("vendor"),
+			Added:   field("added"),
+			Country: field("country"),
+			Address: field("address"),
+			Virtual: field("virtual"),
+		}
+		if v := field("mask"); v != "" {
+			if o.Mask, err = strconv.Atoi(v); err != nil {
+				return nil, &OverrideError{Line: line, Err: fmt.Errorf("invalid mask %q", v)}
+			}
+		}
+		if v := field("private"); v != "" {
+			if o.Private, err = strconv.ParseBool(v); err != nil {
+				return nil, &OverrideError{Line: line, Err: fmt.Errorf("invalid private flag %q", v)}
+			}
+		}
+		records = append(records, o)
+		lines = append(lines, line)
+	}
+	return newOverrideDB(records, lines)
+}
+
+func csvOverrideError(err error) error {
+	var pe *csv.ParseError
+	if errors.As(err, &pe) {
+		return &OverrideError{Line: pe.Line, Err: pe.Err}
+	}
+	return err
+}
+
+// LoadOverridesJSON loads an override table from a JSON array of records.
+func LoadOverridesJSON(r io.Reader) (*OuiDB, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	lineAt := func(offset int64) int {
+		return bytes.Count(data[:offset], []byte{'\n'}) + 1
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, &OverrideError{Line: lineAt(dec.InputOffset()), Err: errors.New("expected a JSON array of records")}
+	}
+
+	var records []OverrideRecord
+	var lines []int
+	for dec.More() {
+		// Skip whitespace so the offset points at the record itself
+		start := dec.InputOffset()
+		for start < int64(len(data)) && strings.IndexByte(" \t\r\n,", data[start]) >= 0 {
+			start++
+		}
+		var o OverrideRecord
+		if err := dec.Decode(&o); err != nil {
+			offset := start
+			var se *json.SyntaxError
+			var te *json.UnmarshalTypeError
+			if errors.As(err, &se) {
+				offset = se.Offset
+			} else if errors.As(err, &te) {
+				offset = te.Offset
+			}
+			return nil, &OverrideError{Line: lineAt(min(offset, int64(len(data)))), Err: err}
+		}
+		records = append(records, o)
+		lines = append(lines, lineAt(start))
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, &OverrideError{Line: lineAt(dec.InputOffset()), Err: err}
+	}
+	return newOverrideDB(records, lines)
+}
+
+// newOverrideDB validates records and builds a database from them.
+func newOverrideDB(records []OverrideRecord, lines []int) (*OuiDB, error) {
+	blocks := make(map[string]*OuiBlock, len(records))
+	seen := make(map[string]int, len(records))
+	for i, o := range records {
+		block, err := o.block()
+		if err != nil {
+			return nil, &OverrideError{Line: lines[i], Err: err}
+		}
+		key := blockKey(block)
+		if prev, dup := seen[key]; dup {
+			return nil, &OverrideError{Line: lines[i], Err: fmt.Errorf("duplicate prefix %s (first defined on line %d)", key, prev)}
+		}
+		seen[key] = lines[i]
+		blocks[key] = block
+	}
+	return NewOuiDB(blocks), nil
+}
+
+// block validates the record and converts it into an OuiBlock.
+func (o OverrideRecord) block() (*OuiBlock, error) {
+	digits, mask := o.Prefix, o.Mask
+	if p, m, found := strings.Cut(digits, "/"); found {
+		n, err := strconv.Atoi(m)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mask in prefix %q", o.Prefix)
+		}
+		if mask != 0 && mask != n {
+			return nil, fmt.Errorf("prefix %q conflicts with mask %d", o.Prefix, mask)
+		}
+		digits, mask = p, n
+	}
+	digits = strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '-', '.', ' ', '_':
+			return -1
+		}
+		return r
+	}, digits)
+	if digits == "" {
+		return nil, errors.New("missing prefix")
+	}
+	if len(digits) > 16 {
+		return nil, fmt.Errorf("prefix %q is longer than 64 bits", o.Prefix)
+	}
+	if mask == 0 {
+		mask = len(digits) * 4
+	}
+	if mask < 1 || mask > 64 {
+		return nil, fmt.Errorf("invalid mask %d", mask)
+	}
+
+	// Masks up to /48 are MAC-48 prefixes, longer ones EUI-64 prefixes
+	width := 12
+	if mask > 48 {
+		width = 16
+	}
+	if len(digits) > width {
+		return nil, fmt.Errorf("prefix %q is longer than %d bits for a /%d", o.Prefix, width*4, mask)
+	}
+	padded := digits + strings.Repeat("0", width-len(digits))
+	oui, err := hex.DecodeString(padded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prefix %q: not hex", o.Prefix)
+	}
+	bits, _, ok := addrBits(oui)
+	if !ok {
+		return nil, fmt.Errorf("invalid prefix %q", o.Prefix)
+	}
+	if bits&^prefixMask(mask) != 0 {
+		return nil, fmt.Errorf("prefix %q has bits set beyond /%d", o.Prefix, mask)
+	}
+
+	if strings.TrimSpace(o.Vendor) == "" {
+		return nil, errors.New("missing vendor")
+	}
+	if o.Added != "" {
+		if _, err := time.Parse("2006-01-02", o.Added); err != nil {
+			return nil, fmt.Errorf("invalid added date %q (want YYYY-MM-DD)", o.Added)
+		}
+	}
+	country := strings.ToUpper(o.Country)
+	if country != "" && (len(country) != 2 || country[0] < 'A' || country[0] > 'Z' || country[1] < 'A' || country[1] > 'Z') {
+		return nil, fmt.Errorf("invalid country code %q", o.Country)
+	}
+
+	vendor := strings.TrimSpace(o.Vendor)
+	short := NormalizeVendor(vendor)
+	return &OuiBlock{
+		Oui:         oui,
+		Mask:        mask,
+		Vendor:      vendor,
+		VendorShort: short,
+		OrgID:       orgID(short),
+		Added:       o.Added,
+		Country:     country,
+		Address:     o.Address,
+		Virtual:     o.Virtual,
+		Private:     o.Private,
+	}, nil
+}
+
+// blockKey returns the masked-prefix key for a block, such as "d0c907000000/24".
+func blockKey(b *OuiBlock) string {
+	return hex.EncodeToString(b.Oui) + "/" + strconv.Itoa(b.Mask)
+}
@@ -0,0 +1,124 @@
+package mactracker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadOverridesCSV(t *testing.T) {
+	input := `# Lab inventory
+prefix,vendor,added,country,private,virtual
+d0:c9:07,Govee,2023-12-14,us,true,
+70:b3:d5:c3:c/36,Lab Sensor,,,true,
+02:42:ac:11/32,Docker Bridge,,,,Docker
+`
+	db, err := LoadOverridesCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadOverridesCSV: %v", err)
+	}
+	if len(db.Blocks) != 3 {
+		t.Fatalf("got %d blocks, want 3", len(db.Blocks))
+	}
+
+	block := db.Blocks["d0c907000000/24"]
+	if block == nil || block.Vendor != "Govee" || !block.Private || block.Country != "US" || block.Added != "2023-12-14" {
+		t.Errorf("Govee block = %+v", block)
+	}
+	if block := db.Lookup(OuiHardwareAddr{0x70, 0xb3, 0xd5, 0xc3, 0xc0, 0x01}); block == nil || block.Vendor != "Lab Sensor" || block.Mask != 36 {
+		t.Errorf("Lookup of /36 override = %+v", block)
+	}
+	if block := db.Lookup(OuiHardwareAddr{0x02, 0x42, 0xac, 0x11, 0x00, 0x02}); block == nil || block.Virtual != "Docker" {
+		t.Errorf("Lookup of /32 override = %+v", block)
+	}
+
+	r := NewResolver(&OUITable)
+	r.PrependTable(db)
+	if block := r.Lookup("70:b3:d5:c3:c0:01"); block == nil || block.Vendor != "Lab Sensor" {
+		t.Errorf("Resolver lookup with overrides = %+v, want Lab Sensor", block)
+	}
+	if block := r.Lookup("70:b3:d5:c3:d0:01"); block == nil || block.Vendor == "Lab Sensor" {
+		t.Errorf("Resolver lookup outside overrides = %+v, want an IEEE block", block)
+	}
+}
+
+func TestLoadOverridesJSON(t *testing.T) {
+	input := `[
+  {"prefix": "d0c907", "vendor": "Govee", "added": "2023-12-14", "private": true},
+  {"prefix": "70b3d5c3c", "mask": 36, "vendor": "Lab Sensor"}
+]`
+	db, err := LoadOverridesJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadOverridesJSON: %v", err)
+	}
+	if block := db.Blocks["70b3d5c3c000/36"]; block == nil || block.Vendor != "Lab Sensor" {
+		t.Errorf("Lab Sensor block = %+v", block)
+	}
+	if block := db.Blocks["d0c907000000/24"]; block == nil || !block.Private {
+		t.Errorf("Govee block = %+v", block)
+	}
+}
+
+func TestLoadOverridesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		csv   bool
+		input string
+		line  int
+		want  string
+	}{
+		{name: "bad hex", csv: true, input: "prefix,vendor\nd0c907,Govee\nzz1122,Bad\n", line: 3, want: "not hex"},
+		{name: "bits past mask", csv: true, input: "prefix,mask,vendor\n\nd0c907,16,Govee\n", line: 3, want: "beyond /16"},
+		{name: "too long for mask", csv: true, input: "prefix,vendor\nd0c907,Govee\n00112233445566/24,Bad\n", line: 3, want: "longer than 48 bits"},
+		{name: "too long", csv: true, input: "prefix,vendor\n00112233445566778/64,Bad\n", line: 2, want: "longer than 64 bits"},
+		{name: "missing header", csv: true, input: "# nothing here\n", line: 1, want: "missing header row"},
+		{name: "missing vendor", csv: true, input: "prefix,vendor\nd0c907,\n", line: 2, want: "missing vendor"},
+		{name: "bad date", csv: true, input: "prefix,vendor,added\nd0c907,Govee,12/14/2023\n", line: 2, want: "invalid added date"},
+		{name: "bad flag", csv: true, input: "prefix,vendor,private\nd0c907,Govee,maybe\n", line: 2, want: "invalid private flag"},
+		{name: "duplicate", csv: true, input: "prefix,vendor\nd0c907,Govee\nd0:c9:07/24,Govee\n", line: 3, want: "first defined on line 2"},
+		{name: "unknown column", csv: true, input: "prefix,vendor,colour\n", line: 1, want: "unknown column"},
+		{name: "json bad country", input: "[\n  {\"prefix\": \"d0c907\", \"vendor\": \"Govee\"},\n  {\"prefix\": \"d0c908\", \"vendor\": \"Govee\", \"country\": \"USA\"}\n]", line: 3, want: "invalid country"},
+		{name: "json unknown field", input: "[\n  {\"prefix\": \"d0c907\",\n   \"vendr\": \"Govee\"}\n]", line: 2, want: "unknown field"},
+		{name: "json wrong type", input: "[\n  {\"prefix\": \"d0c907\", \"vendor\": \"Govee\",\n   \"mask\": \"24\"}\n]", line: 3, want: "cannot unmarshal"},
+	}
+	for _, test := range tests {
+		var err error
+		if test.csv {
+			_, err = LoadOverridesCSV(strings.NewReader(test.input))
+		} else {
+			_, err = LoadOverridesJSON(strings.NewReader(test.input))
+		}
+		var oe *OverrideError
+		if !errors.As(err, &oe) {
+			t.Errorf("%s: got error %v, want *OverrideError", test.name, err)
+			continue
+		}
+		if oe.Line != test.line || !strings.Contains(oe.Error(), test.want) {
+			t.Errorf("%s: got %q on line %d, want %q on line %d", test.name, oe.Error(), oe.Line, test.want, test.line)
+		}
+	}
+}
+
+func TestLoadOverridesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.csv")
+	if err := os.WriteFile(path, []byte("prefix,vendor\nd0c907,Govee\nd0c9,Short\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := LoadOverridesFile(path)
+	if err != nil {
+		t.Fatalf("LoadOverridesFile: %v", err)
+	}
+	if block := db.Lookup(OuiHardwareAddr{0xd0, 0xc9, 0x08, 0x00, 0x00, 0x01}); block == nil || block.Vendor != "Short" {
+		t.Errorf("Lookup of /16 override = %+v, want Short", block)
+	}
+
+	bad := filepath.Join(t.TempDir(), "overrides.json")
+	if err := os.WriteFile(bad, []byte("[{\"prefix\": \"\", \"vendor\": \"Nobody\"}]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOverridesFile(bad); err == nil || !strings.HasPrefix(err.Error(), bad+":1:") {
+		t.Errorf("LoadOverridesFile error = %v, want it prefixed with %s:1:", err, bad)
+	}
+}
@@ -0,0 +1,119 @@
+package mactracker
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors wrapped by MACSyntaxError, describing why ParseMACStrict rejected its input.
+var (
+	ErrMACEmpty           = errors.New("empty address")
+	ErrMACInvalidChar     = errors.New("invalid character")
+	ErrMACMixedSeparators = errors.New("mixed separators")
+	ErrMACGroupLength     = errors.New("wrong number of hex digits in group")
+	ErrMACLength          = errors.New("address must be 6, 8 or 20 bytes")
+)
+
+// MACSyntaxError is returned by ParseMACStrict for malformed input.
+type MACSyntaxError struct {
+	Input  string // The rejected input
+	Offset int    // Byte offset of the problem, or -1 when it applies to the whole input
+	Err    error  // One of the ErrMAC* errors
+}
+
+func (e *MACSyntaxError) Error() string {
+	if e.Offset < 0 {
+		return fmt.Sprintf("invalid MAC address %q: %v", e.Input, e.Err)
+	}
+	return fmt.Sprintf("invalid MAC address %q: %v at offset %d", e.Input, e.Err, e.Offset)
+}
+
+func (e *MACSyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// ParseMACStrict parses s as a MAC-48/EUI-48 (6 bytes), EUI-64 (8 bytes) or
+// 20-byte IP over InfiniBand link-layer address written in exactly one of
+// these notations:
+//
+//	01:23:45:67:89:ab        two hex digits per group, colon separated
+//	01-23-45-67-89-ab        two hex digits per group, dash separated
+//	0123.4567.89ab           four hex digits per group, dot separated
+//	0123456789ab             bare hex
+//
+// Unlike ParseMAC, which strips separators wherever they appear, it rejects
+// mixed separators, short or long groups, stray characters and other lengths,
+// returning a *MACSyntaxError that wraps one of the ErrMAC* errors.
+func ParseMACStrict(s string) (OuiHardwareAddr, error) {
+	if s == "" {
+		return nil, &MACSyntaxError{Input: s, Offset: -1, Err: ErrMACEmpty}
+	}
+
+	// The first non-hex character decides the notation
+	var sep byte
+	for i := 0; i < len(s); i++ {
+		if _, ok := fromHexChar(s[i]); ok {
+			continue
+		}
+		switch s[i] {
+		case ':', '-', '.':
+			sep = s[i]
+		default:
+			return nil, &MACSyntaxError{Input: s, Offset: i, Err: ErrMACInvalidChar}
+		}
+		break
+	}
+
+	groupLen := 0
+	switch sep {
+	case ':', '-':
+		groupLen = 2
+	case '.':
+		groupLen = 4
+	}
+
+	addr := make(OuiHardwareAddr, 0, 20)
+	digits := 0
+	var hi byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if n, ok := fromHexChar(c); ok {
+			digits++
+			if groupLen > 0 && digits > groupLen {
+				return nil, &MACSyntaxError{Input: s, Offset: i, Err: ErrMACGroupLength}
+			}
+			if len(addr) == 20 && digits%2 == 1 {
+				return nil, &MACSyntaxError{Input: s, Offset: -1, Err: ErrMACLength}
+			}
+			if digits%2 == 1 {
+				hi = n
+			} else {
+				addr = append(addr, hi<<4|n)
+			}
+			continue
+		}
+		switch c {
+		case ':', '-', '.':
+			if c != sep {
+				return nil, &MACSyntaxError{Input: s, Offset: i, Err: ErrMACMixedSeparators}
+			}
+			if digits != groupLen {
+				return nil, &MACSyntaxError{Input: s, Offset: i, Err: ErrMACGroupLength}
+			}
+			digits = 0
+		default:
+			return nil, &MACSyntaxError{Input: s, Offset: i, Err: ErrMACInvalidChar}
+		}
+	}
+	if groupLen > 0 && digits != groupLen {
+		return nil, &MACSyntaxError{Input: s, Offset: len(s), Err: ErrMACGroupLength}
+	}
+	if digits%2 == 1 {
+		return nil, &MACSyntaxError{Input: s, Offset: -1, Err: ErrMACLength}
+	}
+	switch len(addr) {
+	case 6, 8, 20:
+		return addr, nil
+	}
+	return nil, &MACSyntaxError{Input: s, Offset: -1, Err: ErrMACLength}
+}
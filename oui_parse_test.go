@@ -0,0 +1,69 @@
+package mactracker
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMACStrict(t *testing.T) {
+	valid := []struct {
+		input string
+		want  string
+	}{
+		{input: "00:1b:c5:00:02:03", want: "00:1b:c5:00:02:03"},
+		{input: "00-1B-C5-00-02-03", want: "00:1b:c5:00:02:03"},
+		{input: "001b.c500.0203", want: "00:1b:c5:00:02:03"},
+		{input: "001bc5000203", want: "00:1b:c5:00:02:03"},
+		{input: "00:1b:c5:ff:fe:00:02:03", want: "00:1b:c5:ff:fe:00:02:03"},
+		{input: "001b.c5ff.fe00.0203", want: "00:1b:c5:ff:fe:00:02:03"},
+		{input: "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01", want: "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01"},
+	}
+	for _, test := range valid {
+		addr, err := ParseMACStrict(test.input)
+		if err != nil {
+			t.Errorf("ParseMACStrict(%q) error = %v", test.input, err)
+			continue
+		}
+		if addr.String() != test.want {
+			t.Errorf("ParseMACStrict(%q) = %s, want %s", test.input, addr, test.want)
+		}
+	}
+
+	invalid := []struct {
+		input  string
+		err    error
+		offset int
+	}{
+		{input: "", err: ErrMACEmpty, offset: -1},
+		{input: "0:1:2", err: ErrMACGroupLength, offset: 1},
+		{input: "00:11:22:33:44", err: ErrMACLength, offset: -1},
+		{input: "00:11:22:33:44:55:66", err: ErrMACLength, offset: -1},
+		{input: "00112233445566", err: ErrMACLength, offset: -1},
+		{input: "00112233445", err: ErrMACLength, offset: -1},
+		{input: "00:11-22:33:44:55", err: ErrMACMixedSeparators, offset: 5},
+		{input: "00:11:22:33:44:555", err: ErrMACGroupLength, offset: 17},
+		{input: "00:11:22:33:44:", err: ErrMACGroupLength, offset: 15},
+		{input: "00::11:22:33:44", err: ErrMACGroupLength, offset: 3},
+		{input: "0011.2233.445", err: ErrMACGroupLength, offset: 13},
+		{input: "00:11:22:33:44:5g", err: ErrMACInvalidChar, offset: 16},
+		{input: " 00:11:22:33:44:55", err: ErrMACInvalidChar, offset: 0},
+		{input: "[00:11:22:33:44:55]", err: ErrMACInvalidChar, offset: 0},
+		{input: "00112233445566778899aabbccddeeff0011223344", err: ErrMACLength, offset: -1},
+	}
+	for _, test := range invalid {
+		_, err := ParseMACStrict(test.input)
+		var se *MACSyntaxError
+		if !errors.As(err, &se) || !errors.Is(err, test.err) {
+			t.Errorf("ParseMACStrict(%q) error = %v, want %v", test.input, err, test.err)
+			continue
+		}
+		if se.Offset != test.offset {
+			t.Errorf("ParseMACStrict(%q) offset = %d, want %d", test.input, se.Offset, test.offset)
+		}
+	}
+
+	// The lenient parser still accepts loosely formatted input
+	if _, err := ParseMAC("00:11-22.33 44_55"); err != nil {
+		t.Errorf("ParseMAC(%q) error = %v", "00:11-22.33 44_55", err)
+	}
+}
@@ -0,0 +1,230 @@
+package mactracker
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Resolver resolves MAC addresses against an ordered set of OUI tables, with
+// its own skip list and set of mask widths. The first table with a matching
+// block wins, so overrides go ahead of the official registrations.
+//
+// A Resolver is safe for concurrent use; configuration changes are applied
+// atomically and never block lookups.
+type Resolver struct {
+	mu    sync.Mutex
+	state atomic.Pointer[resolverState]
+}
+
+// resolverState is an immutable snapshot of a resolver's configuration.
+type resolverState struct {
+	tables     []*OuiDB
+	skip       map[ouiPrefix]struct{}
+	masks      *maskSet
+	strictLAA  bool
+	activeOnly bool
+	bitOrder   BitOrder
+}
+
+// maskSet is a bitmap of the CIDR mask widths (0-64) considered during lookups.
+type maskSet [2]uint64
+
+func (s *maskSet) has(mask uint8) bool {
+	return s == nil || s[mask/64]&(1<<(mask%64)) != 0
+}
+
+// NewResolver returns a resolver over the given tables, in order of priority.
+// The resolver has an empty skip list and considers every mask width.
+func NewResolver(tables ...*OuiDB) *Resolver {
+	r := &Resolver{}
+	r.state.Store(&resolverState{tables: slices.Clone(tables)})
+	return r
+}
+
+// defaultResolver backs the package-level lookup functions.
+var defaultResolver = sync.OnceValue(func() *Resolver {
+	r := &Resolver{}
+	r.state.Store(&resolverState{
+		tables: []*OuiDB{
+			// Well-known group addresses and reserved virtual-router ranges
+			&OUITableMulticast,
+			// Specific overrides for unofficial and private registrations
+			&OUITableExtra,
+			// Virtual machine prefixes (some of which conflict with official registrations)
+			&OUITableVirtual,
+			// The official IEEE OUI registrations
+			ActiveOUITable(),
+		},
+		skip: ouiSkip(),
+	})
+	return r
+})
+
+// DefaultResolver returns the resolver used by Lookup and LookupBytes. It
+// consults OUITableMulticast, OUITableExtra, OUITableVirtual and the active
+// IEEE table, in that order, and skips the prefixes in OUISkipPrefixes.
+func DefaultResolver() *Resolver {
+	return defaultResolver()
+}
+
+// update applies fn to a copy of the current state and publishes the result.
+func (r *Resolver) update(fn func(st *resolverState) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	next := *r.state.Load()
+	if err := fn(&next); err != nil {
+		return err
+	}
+	r.state.Store(&next)
+	return nil
+}
+
+// Tables returns the resolver's tables in order of priority.
+func (r *Resolver) Tables() []*OuiDB {
+	return slices.Clone(r.state.Load().tables)
+}
+
+// SetTables replaces the resolver's tables, in order of priority.
+func (r *Resolver) SetTables(tables ...*OuiDB) {
+	_ = r.update(func(st *resolverState) error {
+		st.tables = slices.Clone(tables)
+		return nil
+	})
+}
+
+// PrependTable adds db ahead of the resolver's existing tables, so its blocks
+// take priority. Use it to layer an override table above the IEEE registrations.
+func (r *Resolver) PrependTable(db *OuiDB) {
+	_ = r.update(func(st *resolverState) error {
+		st.tables = append([]*OuiDB{db}, st.tables...)
+		return nil
+	})
+}
+
+// ReplaceTable swaps every occurrence of old for db and reports whether old was found.
+func (r *Resolver) ReplaceTable(old, db *OuiDB) bool {
+	found := false
+	_ = r.update(func(st *resolverState) error {
+		st.tables = slices.Clone(st.tables)
+		for i, table := range st.tables {
+			if table == old {
+				st.tables[i] = db
+				found = true
+			}
+		}
+		return nil
+	})
+	return found
+}
+
+// SetSkipPrefixes replaces the resolver's skip list. Each key is a masked
+// prefix such as "000000000000/24"; addresses are never attributed to a
+// skipped prefix, falling back to a less specific block instead.
+func (r *Resolver) SetSkipPrefixes(keys ...string) error {
+	skip := make(map[ouiPrefix]struct{}, len(keys))
+	for _, k := range keys {
+		p, ok := parsePrefixKey(k)
+		if !ok {
+			return fmt.Errorf("invalid skip prefix %q", k)
+		}
+		skip[p] = struct{}{}
+	}
+	return r.update(func(st *resolverState) error {
+		st.skip = skip
+		return nil
+	})
+}
+
+// SetMasks limits lookups to blocks with the given mask widths. Calling
+// SetMasks with no arguments restores matching on every mask width.
+func (r *Resolver) SetMasks(masks ...int) error {
+	var set *maskSet
+	if len(masks) > 0 {
+		set = &maskSet{}
+		for _, m := range masks {
+			if m < 0 || m > 64 {
+				return fmt.Errorf("invalid mask width %d", m)
+			}
+			set[m/64] |= 1 << (m % 64)
+		}
+	}
+	return r.update(func(st *resolverState) error {
+		st.masks = set
+		return nil
+	})
+}
+
+// SetStrictLAA controls whether locally administered addresses are only
+// attributed to IEEE Company ID (CID) registrations. In strict mode a
+// randomized address never picks up a vendor from an override, virtual or
+// other non-CID block that happens to cover it. Encoded tables without
+// registries, such as format version 1, can't tell CIDs apart, so strict mode
+// leaves lookups in them unchanged.
+func (r *Resolver) SetStrictLAA(strict bool) {
+	_ = r.update(func(st *resolverState) error {
+		st.strictLAA = strict
+		return nil
+	})
+}
+
+// SetActiveOnly controls whether blocks removed from their IEEE registry are
+// skipped. By default they still resolve, with Removed set, since devices
+// made under a lapsed registration remain in use; when skipped, the address
+// falls through to an enclosing block or the next table. Only tables of
+// format version 2 record removals; see OuiDB.BuildInfo.
+func (r *Resolver) SetActiveOnly(active bool) {
+	_ = r.update(func(st *resolverState) error {
+		st.activeOnly = active
+		return nil
+	})
+}
+
+// IsRandomized reports whether the address looks randomized and is not
+// covered by a Company ID (CID) registration in the resolver's tables, or by
+// any block of an encoded table without registries (see SetStrictLAA).
+func (r *Resolver) IsRandomized(addr OuiHardwareAddr) bool {
+	if len(addr) == 0 || !addr.IsRandomized() {
+		return false
+	}
+	st := *r.state.Load()
+	st.strictLAA = true
+	for _, table := range st.tables {
+		if table.lookup(addr, &st) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Lookup resolves a MAC address string to the best-matching OUI block.
+// Returns nil when the address is unparseable or has no matching registration.
+func (r *Resolver) Lookup(s string) *OuiBlock {
+	addr, err := ParseMAC(s)
+	if err != nil {
+		return nil
+	}
+	return r.LookupBytes(addr)
+}
+
+// LookupBytes is like Lookup but accepts a raw byte-slice address (6 or 8 bytes).
+// It does not allocate.
+func (r *Resolver) LookupBytes(addr []byte) *OuiBlock {
+	st := r.state.Load()
+	if st.bitOrder == BitOrderCanonical {
+		return st.lookup(addr)
+	}
+	block, _ := st.lookupOrder(addr, st.bitOrder)
+	return block
+}
+
+// lookup returns the first matching block in the snapshot's tables.
+func (st *resolverState) lookup(addr []byte) *OuiBlock {
+	for _, table := range st.tables {
+		if block := table.lookup(OuiHardwareAddr(addr), st); block != nil {
+			return block
+		}
+	}
+	return nil
+}
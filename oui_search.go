@@ -0,0 +1,220 @@
+package mactracker
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// VendorMatch selects how SearchVendor compares vendor names.
+type VendorMatch int
+
+const (
+	VendorExact      VendorMatch = iota // The vendor string exactly as registered
+	VendorSubstring                     // A case-insensitive substring of the vendor
+	VendorNormalized                    // The same organization ID (see VendorOrgID)
+)
+
+// SearchVendor returns every block registered to a vendor, ordered by first
+// address with wider blocks first. The search is answered from an index built
+// the first time the database is searched, so lookups alone never pay for it.
+// Skipped prefixes are included, as with Overlapping.
+func (m *OuiDB) SearchVendor(query string, match VendorMatch) []*OuiBlock {
+	x := m.load()
+	m.vendorOnce.Do(func() {
+		m.vendors = newVendorIndex(x)
+	})
+
+	var slots []int
+	switch match {
+	case VendorExact:
+		if slot, ok := m.vendors.exact[query]; ok {
+			slots = []int{slot}
+		}
+	case VendorNormalized:
+		if id := VendorOrgID(query); id != "" {
+			slots = m.vendors.normalized[id]
+		}
+	case VendorSubstring:
+		slots = m.vendors.substring(strings.ToLower(query))
+	}
+
+	var positions []int
+	for _, slot := range slots {
+		positions = append(positions, m.vendors.positions[slot]...)
+	}
+	if len(slots) > 1 {
+		slices.Sort(positions)
+	}
+	res := make([]*OuiBlock, 0, len(positions))
+	for _, i := range positions {
+		res = append(res, x.entries[i].value)
+	}
+	return res
+}
+
+// vendorIndex maps vendor names to the index positions of their blocks. Each
+// distinct name has a slot; substring searches narrow the slots down with a
+// trigram index before checking each candidate name.
+type vendorIndex struct {
+	lower      []string          // Lowercase name of each slot
+	positions  [][]int           // Index positions of each slot's blocks, ascending
+	exact      map[string]int    // Name to slot
+	normalized map[string][]int  // vendorKey of the name to slots, ascending
+	trigrams   map[[3]byte][]int // Trigram of the lowercase name to slots, ascending
+}
+
+func newVendorIndex(x *ouiIndex[*OuiBlock]) *vendorIndex {
+	v := &vendorIndex{
+		exact:      make(map[string]int),
+		normalized: make(map[string][]int),
+		trigrams:   make(map[[3]byte][]int),
+	}
+	for i := range x.entries {
+		name := x.entries[i].value.Vendor
+		if name == "" {
+			continue
+		}
+		slot, ok := v.exact[name]
+		if !ok {
+			slot = len(v.lower)
+			v.exact[name] = slot
+			v.lower = append(v.lower, strings.ToLower(name))
+			v.positions = append(v.positions, nil)
+
+			id := x.entries[i].value.OrgID
+			if id == "" {
+				id = VendorOrgID(name)
+			}
+			if id != "" {
+				v.normalized[id] = append(v.normalized[id], slot)
+			}
+			lower := v.lower[slot]
+			for j := 0; j+3 <= len(lower); j++ {
+				t := [3]byte{lower[j], lower[j+1], lower[j+2]}
+				// Slots are added in increasing order, so a repeated trigram
+				// in the same name is always the last entry
+				if s := v.trigrams[t]; len(s) == 0 || s[len(s)-1] != slot {
+					v.trigrams[t] = append(s, slot)
+				}
+			}
+		}
+		v.positions[slot] = append(v.positions[slot], i)
+	}
+	return v
+}
+
+// substring returns the slots whose lowercase name contains q.
+func (v *vendorIndex) substring(q string) []int {
+	if q == "" {
+		return nil
+	}
+	var candidates []int
+	if len(q) < 3 {
+		// Too short for a trigram, check every name
+		for slot := range v.lower {
+			candidates = append(candidates, slot)
+		}
+	} else {
+		// Start from the rarest trigram and intersect the rest
+		var lists [][]int
+		for j := 0; j+3 <= len(q); j++ {
+			s, ok := v.trigrams[[3]byte{q[j], q[j+1], q[j+2]}]
+			if !ok {
+				return nil
+			}
+			lists = append(lists, s)
+		}
+		slices.SortFunc(lists, func(a, b []int) int { return len(a) - len(b) })
+		candidates = slices.Clone(lists[0])
+		for _, s := range lists[1:] {
+			candidates = intersectSorted(candidates, s)
+			if len(candidates) == 0 {
+				return nil
+			}
+		}
+	}
+
+	res := candidates[:0]
+	for _, slot := range candidates {
+		if strings.Contains(v.lower[slot], q) {
+			res = append(res, slot)
+		}
+	}
+	return res
+}
+
+// intersectSorted keeps the values of a that are also in b. Both must be
+// sorted ascending; a is modified in place.
+func intersectSorted(a, b []int) []int {
+	res := a[:0]
+	j := 0
+	for _, v := range a {
+		for j < len(b) && b[j] < v {
+			j++
+		}
+		if j < len(b) && b[j] == v {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// vendorSuffixes are legal-form words dropped from the end of vendor names
+// when comparing them.
+var vendorSuffixes = map[string]struct{}{
+	"ab": {}, "ag": {}, "as": {}, "asa": {}, "bhd": {}, "bv": {}, "co": {},
+	"company": {}, "corp": {}, "corporation": {}, "gmbh": {}, "inc": {},
+	"incorporated": {}, "kg": {}, "kk": {}, "limited": {}, "llc": {},
+	"ltd": {}, "ltda": {}, "nv": {}, "oy": {}, "plc": {}, "pte": {},
+	"private": {}, "pty": {}, "sa": {}, "sas": {}, "sdn": {}, "spa": {},
+	"srl": {}, "sro": {},
+}
+
+// vendorKey reduces a vendor name to lowercase words without punctuation or
+// trailing legal-form suffixes, so that "Espressif Inc." and "ESPRESSIF INC"
+// compare equal. The first word is always kept.
+func vendorKey(name string) string {
+	buf := make([]byte, 0, len(name))
+	var starts [16]int // Start of the most recent words in buf
+	words := 0
+	inWord := false
+	for _, r := range name {
+		if r < utf8.RuneSelf {
+			switch {
+			case 'a' <= r && r <= 'z', '0' <= r && r <= '9':
+			case 'A' <= r && r <= 'Z':
+				r += 'a' - 'A'
+			default:
+				inWord = false
+				continue
+			}
+		} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			r = unicode.ToLower(r)
+		} else {
+			inWord = false
+			continue
+		}
+		if !inWord {
+			if len(buf) > 0 {
+				buf = append(buf, ' ')
+			}
+			starts[words%len(starts)] = len(buf)
+			words++
+			inWord = true
+		}
+		buf = utf8.AppendRune(buf, r)
+	}
+
+	// Drop trailing suffixes, keeping at least one word
+	end := len(buf)
+	for n := words; n > 1 && words-n < len(starts)-1; n-- {
+		start := starts[(n-1)%len(starts)]
+		if _, ok := vendorSuffixes[string(buf[start:end])]; !ok {
+			break
+		}
+		end = start - 1
+	}
+	return string(buf[:end])
+}
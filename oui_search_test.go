@@ -0,0 +1,92 @@
+package mactracker
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSearchVendor(t *testing.T) {
+	db := NewOuiDB(map[string]*OuiBlock{
+		"001bc5000000/24": {Oui: []byte{0x00, 0x1b, 0xc5, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Espressif Inc."},
+		"240ac4000000/24": {Oui: []byte{0x24, 0x0a, 0xc4, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Espressif Inc."},
+		"0c8b95000000/24": {Oui: []byte{0x0c, 0x8b, 0x95, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "ESPRESSIF INC"},
+		"70b3d5c3c000/36": {Oui: []byte{0x70, 0xb3, 0xd5, 0xc3, 0xc0, 0x00}, Mask: 36, Vendor: "Hangzhou Hikvision Digital Technology Co.,Ltd."},
+		"bc5e33000000/24": {Oui: []byte{0xbc, 0x5e, 0x33, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Prama Hikvision India Private Limited"},
+		"000000000000/24": {Oui: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, Mask: 24, Vendor: ""},
+	})
+
+	keys := func(blocks []*OuiBlock) []string {
+		var res []string
+		for _, b := range blocks {
+			res = append(res, b.Key())
+		}
+		return res
+	}
+	tests := []struct {
+		query string
+		match VendorMatch
+		want  []string
+	}{
+		{query: "Espressif Inc.", match: VendorExact, want: []string{"001bc5000000/24", "240ac4000000/24"}},
+		{query: "espressif inc.", match: VendorExact, want: nil},
+		{query: "espressif", match: VendorNormalized, want: []string{"001bc5000000/24", "0c8b95000000/24", "240ac4000000/24"}},
+		{query: "Espressif, Inc", match: VendorNormalized, want: []string{"001bc5000000/24", "0c8b95000000/24", "240ac4000000/24"}},
+		// Aliases fold subsidiaries into one organization
+		{query: "hangzhou hikvision digital technology", match: VendorNormalized, want: []string{"70b3d5c3c000/36", "bc5e33000000/24"}},
+		{query: "Hikvision", match: VendorNormalized, want: []string{"70b3d5c3c000/36", "bc5e33000000/24"}},
+		{query: "HIKVISION", match: VendorSubstring, want: []string{"70b3d5c3c000/36", "bc5e33000000/24"}},
+		{query: "ik", match: VendorSubstring, want: []string{"70b3d5c3c000/36", "bc5e33000000/24"}},
+		{query: "ssif i", match: VendorSubstring, want: []string{"001bc5000000/24", "0c8b95000000/24", "240ac4000000/24"}},
+		{query: "hikvisions", match: VendorSubstring, want: nil},
+		{query: "", match: VendorSubstring, want: nil},
+		{query: "", match: VendorExact, want: nil},
+		{query: "inc", match: VendorNormalized, want: nil},
+	}
+	for _, test := range tests {
+		got := keys(db.SearchVendor(test.query, test.match))
+		if !slices.Equal(got, test.want) {
+			t.Errorf("SearchVendor(%q, %d) = %v, want %v", test.query, test.match, got, test.want)
+		}
+	}
+}
+
+func TestSearchVendorMatchesScan(t *testing.T) {
+	x := OUITable.load()
+	for _, query := range []string{"espressif", "Hikvision", "ab", "a", "cisco systems", "tp-link", "électronique", "zzzzzz"} {
+		var want []*OuiBlock
+		q := strings.ToLower(query)
+		for i := range x.entries {
+			if b := x.entries[i].value; b.Vendor != "" && strings.Contains(strings.ToLower(b.Vendor), q) {
+				want = append(want, b)
+			}
+		}
+		if got := OUITable.SearchVendor(query, VendorSubstring); !slices.Equal(got, want) {
+			t.Errorf("SearchVendor(%q) returned %d blocks, a scan finds %d", query, len(got), len(want))
+		}
+	}
+}
+
+func TestVendorKey(t *testing.T) {
+	tests := map[string]string{
+		"Espressif Inc.": "espressif",
+		"Hangzhou Hikvision Digital Technology Co.,Ltd.": "hangzhou hikvision digital technology",
+		"TP-LINK TECHNOLOGIES CO.,LTD.":                  "tp link technologies",
+		"Siemens AG":                                     "siemens",
+		"Inc.":                                           "inc",
+		"":                                               "",
+	}
+	for in, want := range tests {
+		if got := vendorKey(in); got != want {
+			t.Errorf("vendorKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func BenchmarkSearchVendor(b *testing.B) {
+	OUITable.SearchVendor("", VendorSubstring)
+	b.ResetTimer()
+	for b.Loop() {
+		OUITable.SearchVendor("hikvision", VendorSubstring)
+	}
+}
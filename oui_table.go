@@ -1,17 +1,104 @@
 package mactracker
 
-import _ "embed"
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
 
 //go:embed oui_table.bin.gz
 var ouiTableData []byte
 
 // OUITable contains IEEE registrations, lazily loaded from embedded binary data.
 var OUITable = OuiDB{
-	loadFunc: func() map[string]*OuiBlock {
-		blocks, err := DecodeOUIDB(ouiTableData)
+	loadFunc: func() (map[string]*OuiBlock, OuiDBInfo) {
+		blocks, info, err := DecodeOUIDBInfo(ouiTableData)
 		if err != nil {
 			panic("mactracker: decode embedded OUI table: " + err.Error())
 		}
-		return blocks
+		return blocks, info
 	},
 }
+
+// activeOUITable is the IEEE table used by the package-level lookups.
+// A nil value means the embedded OUITable.
+var activeOUITable atomic.Pointer[OuiDB]
+
+// NewOuiDB returns a database for the given blocks with its lookup index
+// already built, so it can be swapped in without a first-lookup delay.
+func NewOuiDB(blocks map[string]*OuiBlock) *OuiDB {
+	db := &OuiDB{Blocks: blocks}
+	db.load()
+	return db
+}
+
+// LoadOUIDB reads a database in the gzip-compressed format written by EncodeOUIDB.
+func LoadOUIDB(r io.Reader) (*OuiDB, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	blocks, info, err := DecodeOUIDBInfo(data)
+	if err != nil {
+		return nil, err
+	}
+	db := &OuiDB{Blocks: blocks, Info: info}
+	db.load()
+	return db, nil
+}
+
+// LoadOUIDBFile reads a database file written by EncodeOUIDB, such as oui_table.bin.gz.
+func LoadOUIDBFile(path string) (*OuiDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	db, err := LoadOUIDB(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+// ActiveOUITable returns the IEEE table currently used by LookupOUI and the DefaultResolver.
+func ActiveOUITable() *OuiDB {
+	if db := activeOUITable.Load(); db != nil {
+		return db
+	}
+	return &OUITable
+}
+
+// setOUITableMu serializes SetOUITable callers.
+var setOUITableMu sync.Mutex
+
+// SetOUITable atomically replaces the IEEE table used by the package-level
+// lookups and the DefaultResolver, and returns the previous table. Lookups
+// already in flight finish against the table they started with. Passing nil
+// restores the embedded OUITable.
+func SetOUITable(db *OuiDB) *OuiDB {
+	r := DefaultResolver()
+	setOUITableMu.Lock()
+	defer setOUITableMu.Unlock()
+
+	prev := ActiveOUITable()
+	activeOUITable.Store(db)
+	next := ActiveOUITable()
+	next.load()
+	r.ReplaceTable(prev, next)
+	return prev
+}
+
+// ReloadOUITable loads a database file written by EncodeOUIDB and makes it the
+// active IEEE table. The active table is left unchanged when loading fails.
+func ReloadOUITable(path string) error {
+	db, err := LoadOUIDBFile(path)
+	if err != nil {
+		return err
+	}
+	SetOUITable(db)
+	return nil
+}
@@ -1,6 +1,11 @@
 package mactracker
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
 
 func TestOuiLookup(t *testing.T) {
 	// Test known OUI
@@ -52,3 +57,481 @@ func TestLookupVirtual(t *testing.T) {
 		}
 	}
 }
+
+func TestLookupLongestPrefix(t *testing.T) {
+	tests := []struct {
+		addr OuiHardwareAddr
+		mask int
+	}{
+		// MA-S registration under the IEEE /24
+		{addr: OuiHardwareAddr{0x70, 0xb3, 0xd5, 0xc3, 0xc0, 0x01}, mask: 36},
+		// Falls back to the IEEE /24 for the rest of the block
+		{addr: OuiHardwareAddr{0x70, 0xb3, 0xd5, 0x00, 0x00, 0x01}, mask: 24},
+		// EUI-64 addresses match on the same prefixes
+		{addr: OuiHardwareAddr{0x00, 0x1b, 0xc5, 0x00, 0x00, 0x00, 0x00, 0x01}, mask: 36},
+		{addr: OuiHardwareAddr{0x00, 0x50, 0x56, 0x00, 0x00, 0x00, 0x00, 0x01}, mask: 24},
+	}
+	for _, test := range tests {
+		block := LookupBytes(test.addr)
+		if block == nil || block.Mask != test.mask {
+			t.Errorf("LookupBytes(%s) = %v, want mask %d", test.addr, block, test.mask)
+		}
+	}
+
+	// Truncated and oversized input never matches
+	for _, addr := range []OuiHardwareAddr{{0x00, 0x1b, 0xc5}, {0x00, 0x1b, 0xc5, 0x00, 0x00, 0x00, 0x00}} {
+		if block := LookupBytes(addr); block != nil {
+			t.Errorf("LookupBytes(%s) = %v, want nil", addr, block)
+		}
+	}
+}
+
+func TestOuiDBArbitraryMasks(t *testing.T) {
+	db := &OuiDB{Blocks: map[string]*OuiBlock{
+		"0a0000000000/8":      {Mask: 8, Vendor: "eight"},
+		"0a1000000000/12":     {Mask: 12, Vendor: "twelve"},
+		"0a1234567800/40":     {Mask: 40, Vendor: "forty"},
+		"0a1234567890/44":     {Mask: 44, Vendor: "forty-four"},
+		"0a00000000000000/56": {Mask: 56, Vendor: "fifty-six"},
+		"zz/8":                {Mask: 8, Vendor: "invalid"},
+	}}
+	tests := []struct {
+		addr OuiHardwareAddr
+		want string
+	}{
+		{addr: OuiHardwareAddr{0x0a, 0x12, 0x34, 0x56, 0x78, 0x9a}, want: "forty-four"},
+		{addr: OuiHardwareAddr{0x0a, 0x12, 0x34, 0x56, 0x78, 0x00}, want: "forty"},
+		{addr: OuiHardwareAddr{0x0a, 0x12, 0x34, 0x57, 0x00, 0x00}, want: "twelve"},
+		{addr: OuiHardwareAddr{0x0a, 0x20, 0x00, 0x00, 0x00, 0x00}, want: "eight"},
+		// A /56 cannot match a 48-bit address, but can match an EUI-64
+		{addr: OuiHardwareAddr{0x0a, 0x00, 0x00, 0x00, 0x00, 0x00}, want: "eight"},
+		{addr: OuiHardwareAddr{0x0a, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, want: "fifty-six"},
+		{addr: OuiHardwareAddr{0x0b, 0x00, 0x00, 0x00, 0x00, 0x00}, want: ""},
+	}
+	for _, test := range tests {
+		got := ""
+		if block := db.Lookup(test.addr); block != nil {
+			got = block.Vendor
+		}
+		if got != test.want {
+			t.Errorf("Lookup(%s) = %q, want %q", test.addr, got, test.want)
+		}
+	}
+}
+
+func TestOuiDBDuplicatePrefixes(t *testing.T) {
+	// Both keys name 0a/8; the later key in sorted order wins every time
+	for range 20 {
+		db := &OuiDB{Blocks: map[string]*OuiBlock{
+			"0a/8":           {Mask: 8, Vendor: "short"},
+			"0a0000000000/8": {Mask: 8, Vendor: "long"},
+		}}
+		if block := db.Lookup(OuiHardwareAddr{0x0a, 0x01, 0x02, 0x03, 0x04, 0x05}); block == nil || block.Vendor != "long" {
+			t.Fatalf("Lookup with duplicate prefixes = %v, want long", block)
+		}
+	}
+}
+
+func TestLookupBytesAllocs(t *testing.T) {
+	addr := OuiHardwareAddr{0x70, 0xb3, 0xd5, 0xc3, 0xc0, 0x01}
+	LookupBytes(addr)
+	if n := testing.AllocsPerRun(100, func() { LookupBytes(addr) }); n != 0 {
+		t.Errorf("LookupBytes allocated %v times per call, want 0", n)
+	}
+}
+
+func BenchmarkLookupBytes(b *testing.B) {
+	addrs := []OuiHardwareAddr{
+		{0x00, 0x1b, 0xc5, 0x00, 0x02, 0x03},
+		{0x70, 0xb3, 0xd5, 0xc3, 0xc0, 0x01},
+		{0x50, 0x54, 0x00, 0x12, 0x34, 0x56},
+		{0x02, 0x00, 0x00, 0x00, 0x00, 0x01},
+	}
+	LookupBytes(addrs[0])
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		LookupBytes(addrs[i%len(addrs)])
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	Lookup("00:1b:c5:00:02:03")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Lookup("00:1b:c5:00:02:03")
+	}
+}
+
+func TestLoadOUIDB(t *testing.T) {
+	src := &OuiDB{Blocks: map[string]*OuiBlock{
+		"001bc5000000/24": {Oui: []byte{0x00, 0x1b, 0xc5, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Replacement Vendor", Added: "2026-01-01", Country: "US"},
+	}}
+	data, err := EncodeOUIDB(src)
+	if err != nil {
+		t.Fatalf("EncodeOUIDB: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "oui_table.bin.gz")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ReloadOUITable(filepath.Join(t.TempDir(), "missing.bin.gz")); err == nil {
+		t.Error("ReloadOUITable succeeded for a missing file")
+	}
+	if ActiveOUITable() != &OUITable {
+		t.Fatal("failed reload replaced the active table")
+	}
+
+	if err := ReloadOUITable(path); err != nil {
+		t.Fatalf("ReloadOUITable: %v", err)
+	}
+	defer SetOUITable(nil)
+
+	block := Lookup("00:1b:c5:00:02:03")
+	if block == nil || block.Vendor != "Replacement Vendor" {
+		t.Errorf("Lookup after reload = %v, want Replacement Vendor", block)
+	}
+	if block := LookupOUI("00:50:c2:00:00:01"); block != nil {
+		t.Errorf("LookupOUI after reload = %v, want nil", block)
+	}
+
+	// Overrides are still consulted ahead of the replaced table
+	if block := Lookup("50:54:00:12:34:56"); block == nil || block.Vendor != "QEMU" {
+		t.Errorf("Lookup of virtual prefix after reload = %v, want QEMU", block)
+	}
+
+	if prev := SetOUITable(nil); prev == &OUITable {
+		t.Error("SetOUITable(nil) returned the embedded table as the previous table")
+	}
+	if block := Lookup("00:1b:c5:00:02:03"); block == nil || block.Vendor != "Converging Systems Inc." {
+		t.Errorf("Lookup after restore = %v, want Converging Systems Inc.", block)
+	}
+}
+
+func TestSetOUITableConcurrent(t *testing.T) {
+	db := NewOuiDB(map[string]*OuiBlock{
+		"001bc5000000/24": {Oui: []byte{0x00, 0x1b, 0xc5, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Replacement Vendor"},
+	})
+	defer SetOUITable(nil)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				block := LookupBytes(OuiHardwareAddr{0x00, 0x1b, 0xc5, 0x00, 0x02, 0x03})
+				if block == nil {
+					t.Error("LookupBytes returned nil during table swap")
+					return
+				}
+			}
+		}()
+	}
+	for i := range 100 {
+		if i%2 == 0 {
+			SetOUITable(db)
+		} else {
+			SetOUITable(nil)
+		}
+	}
+	wg.Wait()
+}
+
+func TestResolver(t *testing.T) {
+	lab := &OuiDB{Blocks: map[string]*OuiBlock{
+		"001bc5000000/24": {Oui: []byte{0x00, 0x1b, 0xc5, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Lab Gear", Private: true},
+	}}
+	ieee := NewResolver(&OUITable)
+	custom := NewResolver(lab, &OUITable)
+
+	addr := OuiHardwareAddr{0x00, 0x1b, 0xc5, 0x00, 0x02, 0x03}
+	if block := ieee.LookupBytes(addr); block == nil || block.Vendor != "Converging Systems Inc." {
+		t.Errorf("ieee.LookupBytes = %v, want Converging Systems Inc.", block)
+	}
+	// The /36 registration is more specific but the lab table has priority
+	if block := custom.LookupBytes(addr); block == nil || block.Vendor != "Lab Gear" {
+		t.Errorf("custom.LookupBytes = %v, want Lab Gear", block)
+	}
+
+	// A new resolver has no skip list, unlike the default resolver
+	if block := ieee.Lookup("00:00:00:00:00:01"); block == nil {
+		t.Error("ieee.Lookup of the zero OUI = nil, want the Xerox registration")
+	}
+	if err := ieee.SetSkipPrefixes("000000000000/24"); err != nil {
+		t.Fatalf("SetSkipPrefixes: %v", err)
+	}
+	if block := ieee.Lookup("00:00:00:00:00:01"); block != nil {
+		t.Errorf("ieee.Lookup of a skipped prefix = %v, want nil", block)
+	}
+	if err := ieee.SetSkipPrefixes("nothex/24"); err == nil {
+		t.Error("SetSkipPrefixes accepted an invalid prefix")
+	}
+
+	// Restricting masks falls back to the enclosing IEEE /24
+	if err := ieee.SetMasks(24); err != nil {
+		t.Fatalf("SetMasks: %v", err)
+	}
+	if block := ieee.Lookup("70:b3:d5:c3:c0:01"); block == nil || block.Mask != 24 {
+		t.Errorf("ieee.Lookup with /24 masks = %v, want the /24 block", block)
+	}
+	if err := ieee.SetMasks(); err != nil {
+		t.Fatalf("SetMasks: %v", err)
+	}
+	if block := ieee.Lookup("70:b3:d5:c3:c0:01"); block == nil || block.Mask != 36 {
+		t.Errorf("ieee.Lookup with all masks = %v, want the /36 block", block)
+	}
+	if err := ieee.SetMasks(65); err == nil {
+		t.Error("SetMasks accepted an invalid mask width")
+	}
+
+	if !custom.ReplaceTable(lab, &OUITableVirtual) {
+		t.Error("ReplaceTable did not find the lab table")
+	}
+	if tables := custom.Tables(); len(tables) != 2 || tables[0] != &OUITableVirtual {
+		t.Errorf("Tables after ReplaceTable = %v", tables)
+	}
+	if custom.ReplaceTable(lab, &OUITableVirtual) {
+		t.Error("ReplaceTable found a table that was already replaced")
+	}
+}
+
+func TestResolverLookupBytesAllocs(t *testing.T) {
+	r := NewResolver(&OUITableExtra, &OUITable)
+	if err := r.SetMasks(24, 36); err != nil {
+		t.Fatal(err)
+	}
+	addr := OuiHardwareAddr{0x70, 0xb3, 0xd5, 0xc3, 0xc0, 0x01}
+	r.LookupBytes(addr)
+	if n := testing.AllocsPerRun(100, func() { r.LookupBytes(addr) }); n != 0 {
+		t.Errorf("Resolver.LookupBytes allocated %v times per call, want 0", n)
+	}
+}
+
+func TestIsRandomized(t *testing.T) {
+	tests := []struct {
+		addr       OuiHardwareAddr
+		randomized bool
+		quadrant   string
+	}{
+		{addr: OuiHardwareAddr{0x00, 0x1b, 0xc5, 0x00, 0x02, 0x03}, randomized: false, quadrant: ""},
+		{addr: OuiHardwareAddr{0x5a, 0x12, 0x34, 0x56, 0x78, 0x9a}, randomized: true, quadrant: SLAPELI},
+		{addr: OuiHardwareAddr{0x12, 0x12, 0x34, 0x56, 0x78, 0x9a}, randomized: true, quadrant: SLAPAAI},
+		{addr: OuiHardwareAddr{0x16, 0x12, 0x34, 0x56, 0x78, 0x9a}, randomized: true, quadrant: SLAPReserved},
+		{addr: OuiHardwareAddr{0x1e, 0x12, 0x34, 0x56, 0x78, 0x9a}, randomized: true, quadrant: SLAPSAI},
+		{addr: OuiHardwareAddr{0x03, 0x00, 0x00, 0x00, 0x00, 0x01}, randomized: false, quadrant: SLAPAAI},
+	}
+	for _, test := range tests {
+		if got := test.addr.IsRandomized(); got != test.randomized {
+			t.Errorf("%s.IsRandomized() = %v, want %v", test.addr, got, test.randomized)
+		}
+		if got := test.addr.SLAPQuadrant(); got != test.quadrant {
+			t.Errorf("%s.SLAPQuadrant() = %q, want %q", test.addr, got, test.quadrant)
+		}
+	}
+}
+
+func TestResolverStrictLAA(t *testing.T) {
+	docker := &OuiDB{Blocks: map[string]*OuiBlock{
+		"0242ac000000/24": {Oui: []byte{0x02, 0x42, 0xac, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "Docker", Virtual: "Docker"},
+		"ea2701000000/24": {Oui: []byte{0xea, 0x27, 0x01, 0x00, 0x00, 0x00}, Mask: 24, Vendor: "ACCE Technology Corp.", Registry: RegistryCID},
+	}}
+	r := NewResolver(docker, &OUITable)
+
+	dockerAddr := OuiHardwareAddr{0x02, 0x42, 0xac, 0x11, 0x00, 0x02}
+	cidAddr := OuiHardwareAddr{0xea, 0x27, 0x01, 0x00, 0x00, 0x01}
+	randomAddr := OuiHardwareAddr{0x5a, 0x12, 0x34, 0x56, 0x78, 0x9a}
+
+	if block := r.LookupBytes(dockerAddr); block == nil || block.Vendor != "Docker" {
+		t.Errorf("LookupBytes(%s) = %v, want Docker", dockerAddr, block)
+	}
+
+	r.SetStrictLAA(true)
+	if block := r.LookupBytes(dockerAddr); block != nil {
+		t.Errorf("strict LookupBytes(%s) = %v, want nil", dockerAddr, block)
+	}
+	if block := r.LookupBytes(cidAddr); block == nil || !block.IsCID() {
+		t.Errorf("strict LookupBytes(%s) = %v, want the CID registration", cidAddr, block)
+	}
+	// Universal addresses are unaffected
+	if block := r.Lookup("00:1b:c5:00:02:03"); block == nil {
+		t.Error("strict Lookup of a universal address = nil")
+	}
+
+	if r.IsRandomized(cidAddr) {
+		t.Errorf("IsRandomized(%s) = true for a CID address", cidAddr)
+	}
+	if !r.IsRandomized(randomAddr) || !r.IsRandomized(dockerAddr) {
+		t.Errorf("IsRandomized(%s, %s) = false, want true", randomAddr, dockerAddr)
+	}
+
+	// Tables encoded without registries can't tell CIDs apart, so strict
+	// mode doesn't filter them
+	threeCom := OuiHardwareAddr{0x02, 0x60, 0x8c, 0x12, 0x34, 0x56}
+	blocks := func() map[string]*OuiBlock {
+		return map[string]*OuiBlock{
+			"02608c000000/24": {Oui: []byte{0x02, 0x60, 0x8c, 0, 0, 0}, Mask: 24, Vendor: "3COM"},
+		}
+	}
+	legacy := &OuiDB{Blocks: blocks(), Info: OuiDBInfo{Version: 1}}
+	legacy.load()
+	r.SetTables(legacy)
+	if block := r.LookupBytes(threeCom); block == nil || block.Vendor != "3COM" {
+		t.Errorf("strict LookupBytes(%s) in a version 1 table = %v, want 3COM", threeCom, block)
+	}
+	r.SetTables(NewOuiDB(blocks()))
+	if block := r.LookupBytes(threeCom); block != nil {
+		t.Errorf("strict LookupBytes(%s) in an unencoded table = %v, want nil", threeCom, block)
+	}
+}
+
+func TestSetOUISkipPrefixes(t *testing.T) {
+	zero := OuiHardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x01}
+	if block := Lookup(zero.String()); block != nil {
+		t.Fatalf("Lookup of the zero OUI = %v, want nil", block)
+	}
+
+	t.Cleanup(func() {
+		if err := SetOUISkipPrefixes("000000000000/24"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err := SetOUISkipPrefixes(); err != nil {
+		t.Fatalf("SetOUISkipPrefixes: %v", err)
+	}
+	if block := Lookup(zero.String()); block == nil {
+		t.Error("Lookup of the zero OUI = nil after clearing the skip list")
+	}
+	if block := OUITable.Lookup(zero); block == nil {
+		t.Error("OUITable.Lookup of the zero OUI = nil after clearing the skip list")
+	}
+	if err := SetOUISkipPrefixes("nothex/24"); err == nil {
+		t.Error("SetOUISkipPrefixes accepted an invalid prefix")
+	}
+}
+
+func TestIsCID(t *testing.T) {
+	db := NewOuiDB(map[string]*OuiBlock{
+		"02608c000000/24": {Oui: []byte{0x02, 0x60, 0x8c, 0, 0, 0}, Mask: 24, Vendor: "3COM", Registry: RegistryMAL},
+		"ea2701000000/24": {Oui: []byte{0xea, 0x27, 0x01, 0, 0, 0}, Mask: 24, Vendor: "ACCE Technology Corp.", Registry: RegistryCID},
+	})
+	threeCom := OuiHardwareAddr{0x02, 0x60, 0x8c, 0x12, 0x34, 0x56}
+	cidAddr := OuiHardwareAddr{0xea, 0x27, 0x01, 0x00, 0x00, 0x01}
+	if block := db.Lookup(threeCom); block == nil || block.IsCID() {
+		t.Errorf("Lookup(02:60:8c:12:34:56) = %+v, want a non-CID block", block)
+	}
+	if block := db.Lookup(cidAddr); block == nil || !block.IsCID() {
+		t.Errorf("Lookup(ea:27:01:00:00:01) = %+v, want a CID block", block)
+	}
+
+	// Without a registry the LAA bit of the prefix is not enough
+	if block := OUITable.Lookup(threeCom); block == nil || block.IsCID() {
+		t.Errorf("OUITable.Lookup(02:60:8c:12:34:56) = %+v, want a non-CID block", block)
+	}
+}
+
+func TestResolverActiveOnly(t *testing.T) {
+	db := NewOuiDB(map[string]*OuiBlock{
+		"70b3d5000000/24": {Oui: []byte{0x70, 0xb3, 0xd5, 0, 0, 0}, Mask: 24, Vendor: "IEEE Registration Authority"},
+		"70b3d5c3d000/36": {Oui: []byte{0x70, 0xb3, 0xd5, 0xc3, 0xd0, 0}, Mask: 36, Vendor: "Gone GmbH", Removed: "2018-07-01"},
+	})
+	r := NewResolver(db)
+
+	block := r.Lookup("70:b3:d5:c3:d0:01")
+	if block == nil || block.Vendor != "Gone GmbH" || block.Active() {
+		t.Fatalf("Lookup = %+v, want the removed block", block)
+	}
+	r.SetActiveOnly(true)
+	if block := r.Lookup("70:b3:d5:c3:d0:01"); block == nil || block.Vendor != "IEEE Registration Authority" {
+		t.Errorf("active-only Lookup = %+v, want the enclosing /24", block)
+	}
+}
+
+func TestResolverBitOrder(t *testing.T) {
+	r := NewResolver(&OUITable)
+
+	tests := []struct {
+		mac     string
+		order   BitOrder
+		vendor  string // empty when nothing should match
+		addr    string
+		matched BitOrder
+	}{
+		{mac: "00:00:0c:12:34:56", order: BitOrderCanonical, vendor: "Cisco Systems, Inc", addr: "00:00:0c:12:34:56", matched: BitOrderCanonical},
+		{mac: "10:00:04:48:2c:6a", order: BitOrderCanonical, vendor: "", addr: "10:00:04:48:2c:6a", matched: BitOrderCanonical},
+		// Sun's 08:00:20 printed in Token Ring order only matches once reversed
+		{mac: "10:00:04:48:2c:6a", order: BitOrderAuto, vendor: "Oracle Corporation", addr: "08:00:20:12:34:56", matched: BitOrderReversed},
+		// Canonical matches win in auto mode even when the reversed form is also registered
+		{mac: "00:00:0c:12:34:56", order: BitOrderAuto, vendor: "Cisco Systems, Inc", addr: "00:00:0c:12:34:56", matched: BitOrderCanonical},
+		{mac: "00:00:30:48:2c:6a", order: BitOrderReversed, vendor: "Cisco Systems, Inc", addr: "00:00:0c:12:34:56", matched: BitOrderReversed},
+		// Auto mode leaves addresses that match in neither order as given
+		{mac: "ff:00:0c:12:34:56", order: BitOrderAuto, vendor: "", addr: "ff:00:0c:12:34:56", matched: BitOrderCanonical},
+	}
+	for _, test := range tests {
+		block, addr, matched := r.LookupBitOrder(test.mac, test.order)
+		vendor := ""
+		if block != nil {
+			vendor = block.Vendor
+		}
+		if vendor != test.vendor || addr.String() != test.addr || matched != test.matched {
+			t.Errorf("LookupBitOrder(%s, %s) = %q, %s, %s; want %q, %s, %s",
+				test.mac, test.order, vendor, addr, matched, test.vendor, test.addr, test.matched)
+		}
+	}
+
+	if block := r.Lookup("10:00:04:48:2c:6a"); block != nil {
+		t.Errorf("canonical Lookup = %v, want nil", block)
+	}
+	r.SetBitOrder(BitOrderAuto)
+	addr := OuiHardwareAddr{0x10, 0x00, 0x04, 0x48, 0x2c, 0x6a}
+	if block := r.LookupBytes(addr); block == nil || block.Vendor != "Oracle Corporation" {
+		t.Errorf("auto LookupBytes(%s) = %v, want Oracle Corporation", addr, block)
+	}
+	if n := testing.AllocsPerRun(100, func() { r.LookupBytes(addr) }); n != 0 {
+		t.Errorf("auto LookupBytes allocated %v times per call, want 0", n)
+	}
+	if block, _, _ := r.LookupBitOrder("not a mac", BitOrderAuto); block != nil {
+		t.Errorf("LookupBitOrder of an unparseable address = %v, want nil", block)
+	}
+}
+
+func TestLookupMulticast(t *testing.T) {
+	tests := []struct {
+		mac      string
+		protocol string
+	}{
+		{mac: "01:00:5e:00:00:fb", protocol: "IPv4 multicast"},
+		{mac: "01:00:5e:7f:ff:fa", protocol: "IPv4 multicast"},
+		{mac: "01:00:5e:80:00:01", protocol: "Reserved"},
+		{mac: "33:33:00:00:00:01", protocol: "IPv6 multicast"},
+		{mac: "ff:ff:ff:ff:ff:ff", protocol: "Broadcast"},
+		{mac: "01:80:c2:00:00:00", protocol: "STP"},
+		{mac: "01:80:c2:00:00:02", protocol: "LACP"},
+		{mac: "01:80:c2:00:00:0e", protocol: "LLDP"},
+		{mac: "01:80:c2:00:00:05", protocol: "Link-local control"},
+		{mac: "01:00:0c:cc:cc:cc", protocol: "CDP/VTP/DTP/PAgP/UDLD"},
+		{mac: "00:00:5e:00:01:0a", protocol: "VRRP"},
+		{mac: "00:00:0c:07:ac:01", protocol: "HSRP"},
+		{mac: "00:00:0c:9f:f0:01", protocol: "HSRP"},
+	}
+	for _, test := range tests {
+		block := Lookup(test.mac)
+		if block == nil || block.Protocol != test.protocol || block.Standard == "" {
+			t.Errorf("Lookup(%q) = %+v, want protocol %q", test.mac, block, test.protocol)
+		}
+		if LookupMulticast(test.mac) != block {
+			t.Errorf("LookupMulticast(%q) differs from Lookup", test.mac)
+		}
+	}
+
+	// Ordinary unicast addresses are left to the vendor tables
+	if block := LookupMulticast("00:1b:c5:00:02:03"); block != nil {
+		t.Errorf("LookupMulticast of a unicast address = %+v, want nil", block)
+	}
+	if block := Lookup("00:00:0c:12:34:56"); block == nil || block.Protocol != "" {
+		t.Errorf("Lookup of a Cisco address = %+v, want the IEEE registration", block)
+	}
+}
@@ -0,0 +1,115 @@
+package mactracker
+
+import "math/bits"
+
+// The transforms below never modify the receiver; each returns a newly
+// allocated address.
+
+// clone returns a copy of the address that does not share its backing array.
+func (a OuiHardwareAddr) clone() OuiHardwareAddr {
+	return append(OuiHardwareAddr(nil), a...)
+}
+
+// WithLAA returns a copy of the address with the locally-administered bit set.
+func (a OuiHardwareAddr) WithLAA() OuiHardwareAddr {
+	c := a.clone()
+	if len(c) > 0 {
+		c[0] |= 2
+	}
+	return c
+}
+
+// WithoutLAA returns a copy of the address with the locally-administered bit cleared.
+func (a OuiHardwareAddr) WithoutLAA() OuiHardwareAddr {
+	c := a.clone()
+	if len(c) > 0 {
+		c[0] &^= 2
+	}
+	return c
+}
+
+// WithGroup returns a copy of the address with the individual/group (multicast) bit set.
+func (a OuiHardwareAddr) WithGroup() OuiHardwareAddr {
+	c := a.clone()
+	if len(c) > 0 {
+		c[0] |= 1
+	}
+	return c
+}
+
+// WithoutGroup returns a copy of the address with the individual/group (multicast) bit cleared.
+func (a OuiHardwareAddr) WithoutGroup() OuiHardwareAddr {
+	c := a.clone()
+	if len(c) > 0 {
+		c[0] &^= 1
+	}
+	return c
+}
+
+// ToEUI64 converts a 6-byte address to an EUI-64 by inserting ff:fe after the
+// OUI. Unlike the modified EUI-64 used in IPv6 interface identifiers, the
+// universal/local bit is left as is. Reports false unless the address is 6 bytes.
+func (a OuiHardwareAddr) ToEUI64() (OuiHardwareAddr, bool) {
+	if len(a) != 6 {
+		return nil, false
+	}
+	return OuiHardwareAddr{a[0], a[1], a[2], 0xff, 0xfe, a[3], a[4], a[5]}, true
+}
+
+// FromEUI64 reverses ToEUI64, recovering the 6-byte address from an EUI-64
+// with ff:fe after the OUI. Reports false for any other address.
+func (a OuiHardwareAddr) FromEUI64() (OuiHardwareAddr, bool) {
+	if len(a) != 8 || a[3] != 0xff || a[4] != 0xfe {
+		return nil, false
+	}
+	return OuiHardwareAddr{a[0], a[1], a[2], a[5], a[6], a[7]}, true
+}
+
+// BitReversed returns a copy of the address with the bits of each byte
+// reversed, converting between canonical (Ethernet) order and the
+// non-canonical order used by Token Ring and FDDI. Applying it twice returns
+// the original address.
+func (a OuiHardwareAddr) BitReversed() OuiHardwareAddr {
+	c := make(OuiHardwareAddr, len(a))
+	for i, v := range a {
+		c[i] = bits.Reverse8(v)
+	}
+	return c
+}
+
+// Increment returns the next address within the block formed by the first
+// mask bits, such as 24 for an MA-L. Reports false when the address is the
+// last one in the block, the mask is out of range, or the address is not 6
+// or 8 bytes.
+func (a OuiHardwareAddr) Increment(mask int) (OuiHardwareAddr, bool) {
+	return a.step(mask, 1)
+}
+
+// Decrement returns the previous address within the block formed by the
+// first mask bits. Reports false when the address is the first one in the
+// block, the mask is out of range, or the address is not 6 or 8 bytes.
+func (a OuiHardwareAddr) Decrement(mask int) (OuiHardwareAddr, bool) {
+	return a.step(mask, -1)
+}
+
+func (a OuiHardwareAddr) step(mask int, delta int) (OuiHardwareAddr, bool) {
+	v, width, ok := addrBits(a)
+	if !ok || mask < 0 || mask >= width {
+		return nil, false
+	}
+	// Device bits, shifted down so they can be counted
+	shift := uint(64 - width)
+	host := (v &^ prefixMask(mask)) >> shift
+	switch {
+	case delta > 0 && host == ^prefixMask(mask)>>shift:
+		return nil, false
+	case delta < 0 && host == 0:
+		return nil, false
+	}
+	if delta > 0 {
+		v += 1 << shift
+	} else {
+		v -= 1 << shift
+	}
+	return bitsToAddr(v, width), true
+}
@@ -0,0 +1,116 @@
+package mactracker
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTransformsDoNotAlias(t *testing.T) {
+	orig := OuiHardwareAddr{0x03, 0x1b, 0xc5, 0x00, 0x02, 0x03}
+	want := bytes.Clone(orig)
+
+	transforms := []struct {
+		name string
+		fn   func(OuiHardwareAddr) OuiHardwareAddr
+		res  string
+	}{
+		{"WithLAA", OuiHardwareAddr.WithLAA, "03:1b:c5:00:02:03"},
+		{"WithoutLAA", OuiHardwareAddr.WithoutLAA, "01:1b:c5:00:02:03"},
+		{"WithGroup", OuiHardwareAddr.WithGroup, "03:1b:c5:00:02:03"},
+		{"WithoutGroup", OuiHardwareAddr.WithoutGroup, "02:1b:c5:00:02:03"},
+		{"BitReversed", OuiHardwareAddr.BitReversed, "c0:d8:a3:00:40:c0"},
+	}
+	for _, test := range transforms {
+		got := test.fn(orig)
+		if got.String() != test.res {
+			t.Errorf("%s(%s) = %s, want %s", test.name, orig, got, test.res)
+		}
+		got[5] = 0xff
+		if !bytes.Equal(orig, want) {
+			t.Fatalf("%s modified its receiver: %s", test.name, orig)
+		}
+	}
+
+	// Sub-slices of a larger buffer must not be written through either
+	buf := []byte{0x02, 0x1b, 0xc5, 0x00, 0x02, 0x03, 0xaa, 0xbb}
+	sub := OuiHardwareAddr(buf[:6])
+	_ = append(sub.WithoutLAA(), 0x99)
+	if buf[0] != 0x02 || buf[6] != 0xaa {
+		t.Errorf("WithoutLAA wrote through to the backing array: % x", buf)
+	}
+}
+
+func TestBitReversedRoundTrip(t *testing.T) {
+	addr := OuiHardwareAddr{0x00, 0x00, 0x0c, 0x12, 0x34, 0x56}
+	rev := addr.BitReversed()
+	if rev.String() != "00:00:30:48:2c:6a" {
+		t.Errorf("BitReversed(%s) = %s", addr, rev)
+	}
+	if !bytes.Equal(rev.BitReversed(), addr) {
+		t.Errorf("BitReversed twice = %s, want %s", rev.BitReversed(), addr)
+	}
+}
+
+func TestEUI64Conversion(t *testing.T) {
+	addr := OuiHardwareAddr{0x00, 0x1b, 0xc5, 0x00, 0x02, 0x03}
+	eui, ok := addr.ToEUI64()
+	if !ok || eui.String() != "00:1b:c5:ff:fe:00:02:03" {
+		t.Fatalf("ToEUI64(%s) = %s, %v", addr, eui, ok)
+	}
+	back, ok := eui.FromEUI64()
+	if !ok || !bytes.Equal(back, addr) {
+		t.Errorf("FromEUI64(%s) = %s, %v", eui, back, ok)
+	}
+
+	if _, ok := eui.ToEUI64(); ok {
+		t.Error("ToEUI64 accepted an 8-byte address")
+	}
+	if _, ok := addr.FromEUI64(); ok {
+		t.Error("FromEUI64 accepted a 6-byte address")
+	}
+	if _, ok := (OuiHardwareAddr{0x00, 0x1b, 0xc5, 0x12, 0x34, 0x00, 0x02, 0x03}).FromEUI64(); ok {
+		t.Error("FromEUI64 accepted an EUI-64 without ff:fe")
+	}
+}
+
+func TestIncrementDecrement(t *testing.T) {
+	tests := []struct {
+		addr string
+		mask int
+		next string // empty when there is no next address
+		prev string // empty when there is no previous address
+	}{
+		{addr: "00:1b:c5:00:02:03", mask: 24, next: "00:1b:c5:00:02:04", prev: "00:1b:c5:00:02:02"},
+		{addr: "00:1b:c5:00:02:ff", mask: 24, next: "00:1b:c5:00:03:00", prev: "00:1b:c5:00:02:fe"},
+		{addr: "00:1b:c5:ff:ff:ff", mask: 24, next: "", prev: "00:1b:c5:ff:ff:fe"},
+		{addr: "00:1b:c5:00:00:00", mask: 24, next: "00:1b:c5:00:00:01", prev: ""},
+		{addr: "70:b3:d5:c3:cf:ff", mask: 36, next: "", prev: "70:b3:d5:c3:cf:fe"},
+		{addr: "70:b3:d5:c3:c0:00", mask: 36, next: "70:b3:d5:c3:c0:01", prev: ""},
+		{addr: "00:1b:c5:ff:fe:ff:ff:ff", mask: 24, next: "00:1b:c5:ff:ff:00:00:00", prev: "00:1b:c5:ff:fe:ff:ff:fe"},
+		{addr: "00:1b:c5:00:02:03", mask: 48, next: "", prev: ""},
+		{addr: "00:1b:c5:00:02:03", mask: -1, next: "", prev: ""},
+	}
+	for _, test := range tests {
+		addr, err := ParseMAC(test.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		orig := bytes.Clone(addr)
+
+		next, ok := addr.Increment(test.mask)
+		if ok != (test.next != "") || (ok && next.String() != test.next) {
+			t.Errorf("Increment(%s, %d) = %s, %v, want %q", test.addr, test.mask, next, ok, test.next)
+		}
+		prev, ok := addr.Decrement(test.mask)
+		if ok != (test.prev != "") || (ok && prev.String() != test.prev) {
+			t.Errorf("Decrement(%s, %d) = %s, %v, want %q", test.addr, test.mask, prev, ok, test.prev)
+		}
+		if !bytes.Equal(addr, orig) {
+			t.Errorf("Increment/Decrement modified %s", test.addr)
+		}
+	}
+
+	if _, ok := (OuiHardwareAddr{0x00, 0x1b, 0xc5}).Increment(16); ok {
+		t.Error("Increment accepted a 3-byte address")
+	}
+}
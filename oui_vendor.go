@@ -0,0 +1,182 @@
+package mactracker
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// VendorAliases maps the comparison form of a registered organization name
+// to the short name it is reported under, folding subsidiaries and the many
+// spellings of large vendors together. Keys are lowercase words separated by
+// single spaces, with punctuation and trailing company suffixes (Inc., Co.,
+// Ltd., GmbH, ...) removed, so "Huawei Technologies Co., Ltd." is listed as
+// "huawei technologies". Changes must not race with NormalizeVendor.
+var VendorAliases = map[string]string{
+	"amazon com":                     "Amazon",
+	"amazon technologies":            "Amazon",
+	"apple":                          "Apple",
+	"asustek computer":               "ASUS",
+	"azurewave technology":           "AzureWave",
+	"beijing xiaomi electronics":     "Xiaomi",
+	"beijing xiaomi mobile software": "Xiaomi",
+	"cisco":                          "Cisco",
+	"cisco linksys":                  "Linksys",
+	"cisco meraki":                   "Cisco",
+	"cisco spvtg":                    "Cisco",
+	"cisco systems":                  "Cisco",
+	"cisco systems norway":           "Cisco",
+	"d link":                         "D-Link",
+	"d link international":           "D-Link",
+	"d link systems":                 "D-Link",
+	"dell":                           "Dell",
+	"dell emc":                       "Dell",
+	"dell technologies":              "Dell",
+	"extreme networks headquarters":  "Extreme Networks",
+	"facebook":                       "Meta",
+	"google":                         "Google",
+	"guangdong oppo mobile telecommunications":   "OPPO",
+	"hangzhou hikvision digital technology":      "Hikvision",
+	"hewlett packard":                            "HP",
+	"hewlett packard enterprise":                 "HPE",
+	"hon hai precision ind":                      "Hon Hai",
+	"hon hai precision industry":                 "Hon Hai",
+	"huawei device":                              "Huawei",
+	"huawei technologies":                        "Huawei",
+	"intel":                                      "Intel",
+	"intel corporate":                            "Intel",
+	"lg electronics":                             "LG",
+	"lg electronics mobile communications":       "LG",
+	"liteon technology":                          "Lite-On",
+	"mediatek":                                   "MediaTek",
+	"microsoft mobile":                           "Microsoft",
+	"motorola mobility llc a lenovo":             "Motorola",
+	"murata manufacturing":                       "Murata",
+	"oneplus technology shenzhen":                "OnePlus",
+	"prama hikvision india":                      "Hikvision",
+	"qualcomm":                                   "Qualcomm",
+	"raspberry pi foundation":                    "Raspberry Pi",
+	"raspberry pi trading":                       "Raspberry Pi",
+	"realme chongqing mobile telecommunications": "realme",
+	"realtek semiconductor":                      "Realtek",
+	"samsung electronics":                        "Samsung",
+	"seiko epson":                                "Epson",
+	"sony interactive entertainment":             "Sony",
+	"super micro computer":                       "Supermicro",
+	"tp link systems":                            "TP-Link",
+	"tp link technologies":                       "TP-Link",
+	"vivo mobile communication":                  "vivo",
+	"vmware":                                     "VMware",
+	"xiaomi communications":                      "Xiaomi",
+	"xiaomi electronics":                         "Xiaomi",
+	"zebra technologies":                         "Zebra",
+	"zhejiang dahua technology":                  "Dahua",
+	"zhejiang dahua technologyco":                "Dahua",
+}
+
+// NormalizeVendor returns the short canonical name of a registered
+// organization: its VendorAliases entry when there is one, otherwise the name
+// with trailing company suffixes and punctuation removed and all-capitals
+// words recased, so "Espressif Inc." and "ESPRESSIF INC" both become
+// "Espressif".
+func NormalizeVendor(name string) string {
+	if alias, ok := VendorAliases[vendorKey(name)]; ok {
+		return alias
+	}
+	return shortVendor(name)
+}
+
+// VendorOrgID returns a stable organization ID for a registered organization
+// name: the NormalizeVendor name as a lowercase slug, such as "tp-link" or
+// "hon-hai". Every spelling that normalizes to the same name shares an ID.
+func VendorOrgID(name string) string {
+	return orgID(NormalizeVendor(name))
+}
+
+// orgID converts a normalized vendor name into a slug.
+func orgID(short string) string {
+	var b strings.Builder
+	b.Grow(len(short))
+	dash := false
+	for _, r := range short {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// shortVendor strips trailing company suffixes from a name, keeping its
+// original spelling otherwise. Names written entirely in capitals have words
+// longer than three letters recased, leaving short acronyms such as "LG" alone.
+func shortVendor(name string) string {
+	const trailing = " \t,.;:-&/"
+	s := strings.TrimRight(strings.TrimSpace(name), trailing)
+	for {
+		// Find the last word
+		j := len(s)
+		for j > 0 {
+			r, size := utf8.DecodeLastRuneInString(s[:j])
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+			j -= size
+		}
+		if j == 0 {
+			break
+		}
+		if _, ok := vendorSuffixes[strings.ToLower(s[j:])]; !ok {
+			break
+		}
+		rest := strings.TrimRight(s[:j], trailing)
+		if rest == "" {
+			break
+		}
+		s = rest
+	}
+
+	if !allCaps(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	start := 0
+	for i, r := range s {
+		if !unicode.IsLetter(r) {
+			b.WriteString(recaseWord(s[start:i]))
+			b.WriteRune(r)
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	b.WriteString(recaseWord(s[start:]))
+	return b.String()
+}
+
+// allCaps reports whether s has upper case letters and no lower case ones.
+func allCaps(s string) bool {
+	upper := false
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			return false
+		case unicode.IsUpper(r):
+			upper = true
+		}
+	}
+	return upper
+}
+
+// recaseWord turns an all-capitals word longer than three letters into title case.
+func recaseWord(w string) string {
+	if utf8.RuneCountInString(w) <= 3 {
+		return w
+	}
+	r, size := utf8.DecodeRuneInString(w)
+	return string(r) + strings.ToLower(w[size:])
+}
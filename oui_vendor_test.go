@@ -0,0 +1,62 @@
+package mactracker
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeVendor(t *testing.T) {
+	tests := []struct {
+		name  string
+		short string
+		id    string
+	}{
+		{name: "Apple, Inc.", short: "Apple", id: "apple"},
+		{name: "APPLE INC.", short: "Apple", id: "apple"},
+		{name: "Apple Inc", short: "Apple", id: "apple"},
+		{name: "HUAWEI TECHNOLOGIES CO.,LTD", short: "Huawei", id: "huawei"},
+		{name: "Huawei Device Co., Ltd.", short: "Huawei", id: "huawei"},
+		{name: "TP-LINK TECHNOLOGIES CO.,LTD.", short: "TP-Link", id: "tp-link"},
+		{name: "Prama Hikvision India Private Limited", short: "Hikvision", id: "hikvision"},
+		{name: "Espressif Inc.", short: "Espressif", id: "espressif"},
+		{name: "ESPRESSIF INC", short: "Espressif", id: "espressif"},
+		{name: "Nokia Solutions and Networks GmbH & Co. KG", short: "Nokia Solutions and Networks", id: "nokia-solutions-and-networks"},
+		{name: "SAMSUNG HEAVY INDUSTRIES CO., LTD.", short: "Samsung Heavy Industries", id: "samsung-heavy-industries"},
+		{name: "NEC CORPORATION", short: "NEC", id: "nec"},
+		{name: "  Texas Instruments  ", short: "Texas Instruments", id: "texas-instruments"},
+		{name: "Inc.", short: "Inc", id: "inc"},
+		{name: "", short: "", id: ""},
+	}
+	for _, test := range tests {
+		if got := NormalizeVendor(test.name); got != test.short {
+			t.Errorf("NormalizeVendor(%q) = %q, want %q", test.name, got, test.short)
+		}
+		if got := VendorOrgID(test.name); got != test.id {
+			t.Errorf("VendorOrgID(%q) = %q, want %q", test.name, got, test.id)
+		}
+	}
+}
+
+func TestVendorAliasKeys(t *testing.T) {
+	// Keys that are not in comparison form can never match
+	for key := range VendorAliases {
+		if got := vendorKey(key); got != key {
+			t.Errorf("VendorAliases key %q is not normalized, want %q", key, got)
+		}
+	}
+}
+
+func TestLookupVendorNames(t *testing.T) {
+	block := Lookup("00:1b:c5:00:02:03")
+	if block == nil {
+		t.Fatal("Lookup(00:1b:c5:00:02:03) = nil")
+	}
+	// Tables of format version 1 predate normalized names
+	if OUITable.BuildInfo().Version >= 2 && (block.VendorShort != "Converging Systems" || block.OrgID != "converging-systems") {
+		t.Errorf("Lookup vendor = %+v", block)
+	}
+	// Normalized searches work either way
+	if !slices.Contains(OUITable.SearchVendor("CONVERGING SYSTEMS, INC", VendorNormalized), block) {
+		t.Errorf("normalized search for Converging Systems is missing %s", block.Key())
+	}
+}